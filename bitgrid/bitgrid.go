@@ -0,0 +1,264 @@
+package bitgrid
+
+import (
+	"math/bits"
+)
+
+// Bits is a fixed length bitset, bit i lives in word i/64
+type Bits []uint64
+
+// Grid holds the cells matching one character both row and column wise
+type Grid struct {
+	Width   int
+	Height  int
+	Rows    []Bits
+	Columns []Bits
+}
+
+func NewBits(length int) Bits {
+
+	return make(Bits, (length+63)/64)
+}
+
+func (b Bits) Set(i int) {
+
+	b[i/64] |= 1 << (i % 64)
+}
+
+func (b Bits) Clear(i int) {
+
+	b[i/64] &^= 1 << (i % 64)
+}
+
+func (b Bits) Has(i int) bool {
+
+	return b[i/64]&(1<<(i%64)) != 0
+}
+
+func (b Bits) Count() int {
+
+	count := 0
+
+	for _, word := range b {
+
+		count += bits.OnesCount64(word)
+	}
+
+	return count
+}
+
+func (b Bits) Equal(other Bits) bool {
+
+	for index := range b {
+
+		if b[index] != other[index] {
+
+			return false
+		}
+	}
+
+	return true
+}
+
+func (b Bits) Clone() Bits {
+
+	return append(Bits(nil), b...)
+}
+
+// Ones returns the indices of all set bits in ascending order
+func (b Bits) Ones() []int {
+
+	var ones []int
+
+	for index, word := range b {
+
+		for word != 0 {
+
+			ones = append(ones, index*64+bits.TrailingZeros64(word))
+			word &= word - 1
+		}
+	}
+
+	return ones
+}
+
+// SetRange sets the bits start to end - 1
+func (b Bits) SetRange(start int, end int) {
+
+	for start < end {
+
+		word := start / 64
+		wordEnd := min(end, (word+1)*64)
+		width := wordEnd - start
+
+		mask := ^uint64(0)
+
+		if width < 64 {
+
+			mask = (1<<width - 1) << (start % 64)
+		}
+
+		b[word] |= mask
+		start = wordEnd
+	}
+}
+
+// CountRange counts the set bits start to end - 1
+func (b Bits) CountRange(start int, end int) int {
+
+	count := 0
+
+	for start < end {
+
+		word := start / 64
+		wordEnd := min(end, (word+1)*64)
+		width := wordEnd - start
+
+		mask := ^uint64(0)
+
+		if width < 64 {
+
+			mask = (1<<width - 1) << (start % 64)
+		}
+
+		count += bits.OnesCount64(b[word] & mask)
+		start = wordEnd
+	}
+
+	return count
+}
+
+func Xor(a Bits, b Bits) Bits {
+
+	result := make(Bits, len(a))
+
+	for index := range a {
+
+		result[index] = a[index] ^ b[index]
+	}
+
+	return result
+}
+
+// Parse sets a bit for every cell of the lines that equals cell
+func Parse(lines []string, cell byte) Grid {
+
+	grid := Grid{Height: len(lines), Width: len(lines[0])}
+
+	for y := 0; y < grid.Height; y++ {
+
+		row := NewBits(grid.Width)
+
+		for x := 0; x < grid.Width; x++ {
+
+			if lines[y][x] == cell {
+
+				row.Set(x)
+			}
+		}
+
+		grid.Rows = append(grid.Rows, row)
+	}
+
+	grid.Columns = Transpose(grid.Rows, grid.Width)
+
+	return grid
+}
+
+// Transpose turns lines of the given length into length lines, it only visits set bits
+func Transpose(lines []Bits, length int) []Bits {
+
+	transposed := make([]Bits, length)
+
+	for index := range transposed {
+
+		transposed[index] = NewBits(len(lines))
+	}
+
+	for lineIndex, line := range lines {
+
+		for _, index := range line.Ones() {
+
+			transposed[index].Set(lineIndex)
+		}
+	}
+
+	return transposed
+}
+
+// Pack moves the set bits of every run between two walls to the start or, if towardsEnd, to the end of the run
+func Pack(line Bits, walls Bits, length int, towardsEnd bool) Bits {
+
+	packed := NewBits(length)
+	PackInto(packed, line, walls, length, towardsEnd)
+
+	return packed
+}
+
+// PackInto is Pack writing into packed, which must not share memory with line
+func PackInto(packed Bits, line Bits, walls Bits, length int, towardsEnd bool) {
+
+	for index := range packed {
+
+		packed[index] = 0
+	}
+
+	start := 0
+
+	packRun := func(wall int) {
+
+		if count := line.CountRange(start, wall); count > 0 {
+
+			if towardsEnd {
+
+				packed.SetRange(wall-count, wall)
+			} else {
+
+				packed.SetRange(start, start+count)
+			}
+		}
+
+		start = wall + 1
+	}
+
+	for index, word := range walls {
+
+		for word != 0 {
+
+			packRun(index*64 + bits.TrailingZeros64(word))
+			word &= word - 1
+		}
+	}
+
+	packRun(length)
+}
+
+// Settle moves the set bits of every line towards line 0 or, if towardsEnd, towards the last line
+// until they hit a wall or another set bit, all columns of a word move at once
+func Settle(lines []Bits, walls []Bits, towardsEnd bool) {
+
+	step := -1
+	first := 1
+
+	if towardsEnd {
+
+		step = 1
+		first = len(lines) - 2
+	}
+
+	// the lines before the current one are settled already, so its bits only move on into free cells
+	for current := first; current >= 0 && current < len(lines); current -= step {
+
+		for index := range lines[current] {
+
+			moving := lines[current][index]
+
+			for line := current; moving != 0 && line+step >= 0 && line+step < len(lines); line += step {
+
+				moving &^= lines[line+step][index] | walls[line+step][index]
+				lines[line][index] &^= moving
+				lines[line+step][index] |= moving
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package bitgrid
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBits(t *testing.T) {
+
+	testIn := NewBits(130)
+	testIn.SetRange(60, 70)
+	testIn.Set(129)
+
+	assert("Count", "60-70 and 129", "11", fmt.Sprintf("%d", testIn.Count()), t)
+	assert("CountRange", "60-70 and 129", "5", fmt.Sprintf("%d", testIn.CountRange(65, 128)), t)
+	assert("Ones", "60-70 and 129", "[60 61 62 63 64 65 66 67 68 69 129]", fmt.Sprintf("%v", testIn.Ones()), t)
+
+	other := testIn.Clone()
+	other.Clear(129)
+
+	assert("Xor", "60-70 and 129", "[129]", fmt.Sprintf("%v", Xor(testIn, other).Ones()), t)
+}
+
+func TestParseAndPack(t *testing.T) {
+
+	testIn := []string{"O.#O.", ".O..O"}
+
+	rocks := Parse(testIn, 'O')
+	walls := Parse(testIn, '#')
+
+	assert("Parse", "rows", "[0 3] [1 4]", fmt.Sprintf("%v %v", rocks.Rows[0].Ones(), rocks.Rows[1].Ones()), t)
+	assert("Parse", "columns", "[0] [1] [] [0] [1]", fmt.Sprintf("%v %v %v %v %v", rocks.Columns[0].Ones(), rocks.Columns[1].Ones(), rocks.Columns[2].Ones(), rocks.Columns[3].Ones(), rocks.Columns[4].Ones()), t)
+	assert("Pack", "towards start", "[0 3]", fmt.Sprintf("%v", Pack(rocks.Rows[0], walls.Rows[0], 5, false).Ones()), t)
+	assert("Pack", "towards end", "[1 4]", fmt.Sprintf("%v", Pack(rocks.Rows[0], walls.Rows[0], 5, true).Ones()), t)
+	assert("Pack", "without walls", "[3 4]", fmt.Sprintf("%v", Pack(rocks.Rows[1], walls.Rows[1], 5, true).Ones()), t)
+}
+
+func TestSettle(t *testing.T) {
+
+	testIn := []string{"..#..", "O....", ".O#O.", "O..OO"}
+	testOut := map[bool]string{
+		false: "[0 1 3 4] [0 3] [] []",
+		true:  "[] [] [0 3] [0 1 3 4]",
+	}
+
+	for towardsEnd, expected := range testOut {
+
+		rocks := Parse(testIn, 'O')
+		Settle(rocks.Rows, Parse(testIn, '#').Rows, towardsEnd)
+
+		assert("Settle", fmt.Sprint(towardsEnd), expected, fmt.Sprintf("%v %v %v %v", rocks.Rows[0].Ones(), rocks.Rows[1].Ones(), rocks.Rows[2].Ones(), rocks.Rows[3].Ones()), t)
+	}
+}
+
+func assert(method string, input string, expected string, received string, t *testing.T) {
+
+	if expected != received {
+
+		t.Errorf("%s(%s) expected '%s' but received '%s'", method, input, expected, received)
+	}
+}
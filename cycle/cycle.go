@@ -0,0 +1,181 @@
+package cycle
+
+// Cycle describes an iterated sequence x0, step(x0), step(step(x0)), ... whose states repeat
+// with period Length from index Start on. A Length of 0 means that no cycle is known.
+type Cycle struct {
+	Start  int
+	Length int
+}
+
+func ByKey[S any, K comparable](key func(S) K) func(S, S) bool {
+
+	return func(a S, b S) bool {
+
+		return key(a) == key(b)
+	}
+}
+
+// Find detects the cycle with Brent's algorithm, the state space must be finite
+func Find[S any](initial S, step func(S) S, equal func(S, S) bool) Cycle {
+
+	// search successive powers of two for the cycle length
+	power := 1
+	length := 1
+	tortoise := initial
+	hare := step(initial)
+
+	for !equal(tortoise, hare) {
+
+		if power == length {
+
+			tortoise = hare
+			power *= 2
+			length = 0
+		}
+
+		hare = step(hare)
+		length++
+	}
+
+	// find the start of the cycle with two pointers at distance length
+	tortoise = initial
+	hare = initial
+
+	for i := 0; i < length; i++ {
+
+		hare = step(hare)
+	}
+
+	start := 0
+
+	for !equal(tortoise, hare) {
+
+		tortoise = step(tortoise)
+		hare = step(hare)
+		start++
+	}
+
+	return Cycle{Start: start, Length: length}
+}
+
+// Walk steps through the states once and hands the state after every step to visit. It stops when visit
+// returns false, after limit steps unless limit is negative, or when Brent's algorithm sees a key repeat.
+// The start of the cycle is searched on the keys seen so far, so step may update the state in place.
+func Walk[S any, K comparable](initial S, step func(S) S, key func(S) K, limit int, visit func(iteration int, state S) bool) (Cycle, bool) {
+
+	keys := []K{key(initial)}
+	power := 1
+	length := 0
+	tortoise := keys[0]
+	state := initial
+
+	for iteration := 1; limit < 0 || iteration <= limit; iteration++ {
+
+		state = step(state)
+
+		if !visit(iteration, state) {
+
+			return Cycle{}, false
+		}
+
+		hare := key(state)
+		keys = append(keys, hare)
+		length++
+
+		if tortoise == hare {
+
+			start := 0
+
+			for keys[start] != keys[start+length] {
+
+				start++
+			}
+
+			return Cycle{Start: start, Length: length}, true
+		}
+
+		if power == length {
+
+			tortoise = hare
+			power *= 2
+			length = 0
+		}
+	}
+
+	return Cycle{}, false
+}
+
+// Reduce maps an iteration to the smallest iteration with the same state
+func Reduce(cycle Cycle, iteration int) int {
+
+	if cycle.Length == 0 || iteration < cycle.Start+cycle.Length {
+
+		return iteration
+	}
+
+	return cycle.Start + (iteration-cycle.Start)%cycle.Length
+}
+
+func StateAt[S any](initial S, step func(S) S, cycle Cycle, iteration int) S {
+
+	state := initial
+
+	for i := 0; i < Reduce(cycle, iteration); i++ {
+
+		state = step(state)
+	}
+
+	return state
+}
+
+// SumsUntil sums a metric of several values over the states at iterations 1 to iteration in a single Walk
+// and skips ahead once a state repeats. The returned Cycle has a Length of 0 if no state repeated.
+func SumsUntil[S any, K comparable](initial S, step func(S) S, key func(S) K, iteration int, metric func(S) []int) ([]int, Cycle) {
+
+	// prefixes[i] holds the sums over iterations 1 to i
+	prefixes := [][]int{nil}
+
+	cycle, repeated := Walk(initial, step, key, iteration, func(current int, state S) bool {
+
+		values := metric(state)
+		prefix := make([]int, len(values))
+
+		for index, value := range values {
+
+			prefix[index] = valueAt(prefixes[current-1], index) + value
+		}
+
+		prefixes = append(prefixes, prefix)
+
+		return true
+	})
+
+	if !repeated {
+
+		return prefixes[len(prefixes)-1], Cycle{}
+	}
+
+	periods := (iteration - cycle.Start) / cycle.Length
+	remainder := (iteration - cycle.Start) % cycle.Length
+	sums := make([]int, len(prefixes[cycle.Start+cycle.Length]))
+
+	for index := range sums {
+
+		before := valueAt(prefixes[cycle.Start], index)
+		period := prefixes[cycle.Start+cycle.Length][index] - before
+
+		sums[index] = valueAt(prefixes[cycle.Start+remainder], index) + periods*period
+	}
+
+	return sums, cycle
+}
+
+func valueAt(values []int, index int) int {
+
+	if index < len(values) {
+
+		return values[index]
+	}
+
+	return 0
+}
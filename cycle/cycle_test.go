@@ -0,0 +1,128 @@
+package cycle
+
+import (
+	"fmt"
+	"testing"
+)
+
+func step(x int) int {
+
+	return (x*x + 1) % 255
+}
+
+func equal(a int, b int) bool {
+
+	return a == b
+}
+
+func TestFind(t *testing.T) {
+
+	for initial := 0; initial < 255; initial++ {
+
+		// naive reference with the index of every state seen
+		seen := map[int]int{}
+		state := initial
+		index := 0
+
+		for {
+
+			if firstIndex, found := seen[state]; found {
+
+				expected := Cycle{Start: firstIndex, Length: index - firstIndex}
+				received := Find(initial, step, equal)
+				assert("Find", fmt.Sprintf("%d", initial), fmt.Sprintf("%v", expected), fmt.Sprintf("%v", received), t)
+				break
+			}
+
+			seen[state] = index
+			state = step(state)
+			index++
+		}
+	}
+}
+
+func TestWalk(t *testing.T) {
+
+	for initial := 0; initial < 255; initial++ {
+
+		visited := 0
+		received, repeated := Walk(initial, step, func(x int) int { return x }, -1, func(iteration int, state int) bool {
+
+			visited = iteration
+			return true
+		})
+
+		expected := Find(initial, step, equal)
+		assert("Walk", fmt.Sprintf("%d", initial), fmt.Sprintf("%v true", expected), fmt.Sprintf("%v %t", received, repeated), t)
+
+		if visited < expected.Start+expected.Length {
+
+			t.Errorf("Walk(%d) stopped after %d steps before one pass through %v", initial, visited, expected)
+		}
+	}
+
+	_, repeated := Walk(0, func(x int) int { return x + 1 }, func(x int) int { return x }, 100, func(int, int) bool { return true })
+
+	assert("Walk", "limit", "false", fmt.Sprintf("%t", repeated), t)
+
+	stoppedAt := 0
+	_, repeated = Walk(0, func(x int) int { return x + 1 }, func(x int) int { return x }, -1, func(iteration int, state int) bool {
+
+		stoppedAt = iteration
+		return state < 10
+	})
+
+	assert("Walk", "visit", "false 10", fmt.Sprintf("%t %d", repeated, stoppedAt), t)
+}
+
+func TestStateAt(t *testing.T) {
+
+	initial := 3
+	cycle := Find(initial, step, equal)
+
+	for _, iteration := range []int{0, 1, 5, 17, 100, 1001} {
+
+		state := initial
+
+		for i := 1; i <= iteration; i++ {
+
+			state = step(state)
+		}
+
+		assert("StateAt", fmt.Sprintf("%d", iteration), fmt.Sprintf("%d", state), fmt.Sprintf("%d", StateAt(initial, step, cycle, iteration)), t)
+	}
+}
+
+func TestSumsUntil(t *testing.T) {
+
+	initial := 3
+	metric := func(x int) []int { return []int{x, 1} }
+
+	for _, iteration := range []int{1, 5, 17, 100, 1001} {
+
+		state := initial
+		sum := 0
+
+		for i := 1; i <= iteration; i++ {
+
+			state = step(state)
+			sum += state
+		}
+
+		sums, _ := SumsUntil(initial, step, func(x int) int { return x }, iteration, metric)
+
+		assert("SumsUntil", fmt.Sprintf("%d", iteration), fmt.Sprintf("[%d %d]", sum, iteration), fmt.Sprintf("%v", sums), t)
+	}
+
+	_, cycle := SumsUntil(0, func(x int) int { return x + 1 }, func(x int) int { return x }, 100, metric)
+
+	assert("SumsUntil", "no repetition", "{0 0}", fmt.Sprintf("%v", cycle), t)
+}
+
+func assert(method string, input string, expected string, received string, t *testing.T) {
+
+	if expected != received {
+
+		t.Errorf("%s(%s) expected '%s' but received '%s'", method, input, expected, received)
+	}
+}
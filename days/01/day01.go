@@ -4,162 +4,254 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"regexp"
-	"strconv"
+	"sort"
 	"strings"
 )
 
 const DAY = "01"
 
-func getLineCalibration(line string) int {
+type Vocabulary map[string]int
 
-	characters := strings.Split(line, "")
+var DigitVocabulary = Vocabulary{
+	"0": 0, "1": 1, "2": 2, "3": 3, "4": 4, "5": 5, "6": 6, "7": 7, "8": 8, "9": 9,
+}
+
+var EnglishVocabulary = extendVocabulary(DigitVocabulary, Vocabulary{
+	"one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6, "seven": 7, "eight": 8, "nine": 9,
+})
+
+type Token struct {
+	word  string
+	value int
+	start int
+	end   int
+}
+
+func (b Token) String() string {
+
+	return fmt.Sprintf("Token(word=%s, value=%d, start=%d, end=%d)", b.word, b.value, b.start, b.end)
+}
+
+type Calibration struct {
+	first  Token
+	last   Token
+	tokens []Token
+	value  int
+}
+
+type trieNode struct {
+	children map[byte]int
+	fail     int
+	outputs  []string
+}
 
-	first := -1
-	last := -1
+// Aho-Corasick automaton over the words of a vocabulary
+type Decoder struct {
+	nodes      []trieNode
+	vocabulary Vocabulary
+}
+
+func extendVocabulary(base Vocabulary, extension Vocabulary) Vocabulary {
+
+	result := make(Vocabulary)
 
-	for _, character := range characters {
+	for word, value := range base {
 
-		intValue, err := strconv.ParseInt(character, 10, 8)
+		result[word] = value
+	}
+
+	for word, value := range extension {
 
-		if err == nil {
+		result[word] = value
+	}
+
+	return result
+}
+
+func newDecoder(vocabulary Vocabulary) Decoder {
+
+	decoder := Decoder{
+		nodes:      []trieNode{{children: make(map[byte]int)}},
+		vocabulary: vocabulary,
+	}
 
-			if first == -1 {
+	for word := range vocabulary {
 
-				first = int(intValue)
-			} else {
+		current := 0
 
-				last = int(intValue)
+		for i := 0; i < len(word); i++ {
+
+			next, found := decoder.nodes[current].children[word[i]]
+
+			if !found {
+
+				next = len(decoder.nodes)
+				decoder.nodes = append(decoder.nodes, trieNode{children: make(map[byte]int)})
+				decoder.nodes[current].children[word[i]] = next
 			}
+
+			current = next
 		}
+
+		decoder.nodes[current].outputs = append(decoder.nodes[current].outputs, word)
 	}
 
-	if last == -1 {
+	// breadth first, so the failure links of shorter suffixes are known already
+	queue := []int{}
+
+	for _, child := range decoder.nodes[0].children {
 
-		last = first
+		queue = append(queue, child)
 	}
 
-	return combine(first, last)
-}
+	for len(queue) > 0 {
 
-func convertToInt(match string) int {
+		current := queue[0]
+		queue = queue[1:]
 
-	value, err := strconv.Atoi(match)
-	if err != nil {
-		switch match {
-		case "one":
-			value = 1
-		case "two":
-			value = 2
-		case "three":
-			value = 3
-		case "four":
-			value = 4
-		case "five":
-			value = 5
-		case "six":
-			value = 6
-		case "seven":
-			value = 7
-		case "eight":
-			value = 8
-		case "nine":
-			value = 9
-		default:
+		for character, child := range decoder.nodes[current].children {
+
+			fail := decoder.nodes[current].fail
+
+			for fail != 0 && !hasChild(decoder.nodes[fail], character) {
+
+				fail = decoder.nodes[fail].fail
+			}
+
+			if next, found := decoder.nodes[fail].children[character]; found && next != child {
+
+				fail = next
+			}
+
+			decoder.nodes[child].fail = fail
+			decoder.nodes[child].outputs = append(decoder.nodes[child].outputs, decoder.nodes[fail].outputs...)
+			queue = append(queue, child)
 		}
 	}
-	return value
+
+	return decoder
 }
 
-func convertToIntReversed(match string) int {
+func hasChild(node trieNode, character byte) bool {
 
-	value, err := strconv.Atoi(match)
-	if err != nil {
-		switch match {
-		case "eno":
-			value = 1
-		case "owt":
-			value = 2
-		case "eerht":
-			value = 3
-		case "ruof":
-			value = 4
-		case "evif":
-			value = 5
-		case "xis":
-			value = 6
-		case "neves":
-			value = 7
-		case "thgie":
-			value = 8
-		case "enin":
-			value = 9
-		default:
+	_, found := node.children[character]
+
+	return found
+}
+
+// finds all tokens including overlapping ones, ordered by start and then by end
+func decode(decoder Decoder, line string) []Token {
+
+	var tokens []Token
+
+	current := 0
+
+	for i := 0; i < len(line); i++ {
+
+		for current != 0 && !hasChild(decoder.nodes[current], line[i]) {
+
+			current = decoder.nodes[current].fail
+		}
+
+		if next, found := decoder.nodes[current].children[line[i]]; found {
+
+			current = next
+		}
+
+		for _, word := range decoder.nodes[current].outputs {
+
+			tokens = append(tokens, Token{
+				word:  word,
+				value: decoder.vocabulary[word],
+				start: i + 1 - len(word),
+				end:   i + 1,
+			})
 		}
 	}
-	return value
+
+	sort.Slice(tokens, func(i, j int) bool {
+
+		if tokens[i].start != tokens[j].start {
+
+			return tokens[i].start < tokens[j].start
+		}
+		return tokens[i].end < tokens[j].end
+	})
+
+	return tokens
 }
 
-func Reverse(s string) string {
-	runes := []rune(s)
-	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
-		runes[i], runes[j] = runes[j], runes[i]
+func getCalibration(decoder Decoder, line string) Calibration {
+
+	var calibration Calibration
+
+	calibration.tokens = decode(decoder, line)
+
+	if len(calibration.tokens) == 0 {
+
+		return calibration
 	}
-	return string(runes)
-}
 
-func combine(val1 int, val2 int) int {
+	// prefer the longest token if several start or end at the same position
+	calibration.first = calibration.tokens[0]
+	calibration.last = calibration.tokens[0]
+
+	for _, token := range calibration.tokens {
+
+		if token.start == calibration.first.start && token.end > calibration.first.end {
 
-	resString := fmt.Sprintf("%d%d", val1, val2)
+			calibration.first = token
+		}
 
-	resInt, _ := strconv.ParseInt(resString, 10, 8)
+		if token.end > calibration.last.end || (token.end == calibration.last.end && token.start < calibration.last.start) {
 
-	return int(resInt)
+			calibration.last = token
+		}
+	}
+
+	calibration.value = combine(calibration.first.value, calibration.last.value)
+
+	return calibration
 }
 
-func getLineCalibrationPart2(line string) int {
+func combine(val1 int, val2 int) int {
 
-	re := regexp.MustCompile(`\d|one|two|three|four|five|six|seven|eight|nine`)
-	first := convertToInt(re.FindString(line))
+	shift := 10
 
-	reReversed := regexp.MustCompile(`\d|enin|thgie|neves|xis|evif|ruof|eerht|owt|eno`)
-	lineReversed := Reverse(line)
+	for shift <= val2 {
 
-	last := convertToIntReversed(reReversed.FindString(lineReversed))
+		shift *= 10
+	}
 
-	return combine(first, last)
+	return val1*shift + val2
 }
 
-func Part1(input string) string {
+func sumCalibrations(content string, vocabulary Vocabulary) int {
 
-	content := GetContent(input)
-
-	lines := strings.Split(content, "\n")
+	decoder := newDecoder(vocabulary)
 
 	sum := 0
 
-	for _, element := range lines {
+	for _, line := range strings.Split(content, "\n") {
 
-		sum += getLineCalibration(element)
+		sum += getCalibration(decoder, line).value
 	}
 
-	return fmt.Sprint(sum)
+	return sum
 }
 
-func Part2(input string) string {
+func Part1(input string) string {
 
 	content := GetContent(input)
 
-	lines := strings.Split(content, "\n")
-
-	sum := 0
+	return fmt.Sprint(sumCalibrations(content, DigitVocabulary))
+}
 
-	for _, element := range lines {
+func Part2(input string) string {
 
-		sum += getLineCalibrationPart2(element)
-	}
+	content := GetContent(input)
 
-	return fmt.Sprint(sum)
+	return fmt.Sprint(sumCalibrations(content, EnglishVocabulary))
 }
 
 func GetContent(filepath string) string {
@@ -11,6 +11,37 @@ var P1_OUT_TEST = [1]string{"142"}
 var P2_IN_TEST = [2]string{fmt.Sprintf("test/%s/in02.txt", DAY), fmt.Sprintf("test/%s/in03.txt", DAY)}
 var P2_OUT_TEST = [2]string{"281", "58"}
 
+func TestGetCalibration(t *testing.T) {
+
+	decoder := newDecoder(EnglishVocabulary)
+
+	testIn := "xeightwone7"
+	testOut := []Token{
+		{word: "eight", value: 8, start: 1, end: 6},
+		{word: "two", value: 2, start: 5, end: 8},
+		{word: "one", value: 1, start: 7, end: 10},
+		{word: "7", value: 7, start: 10, end: 11},
+	}
+
+	calibration := getCalibration(decoder, testIn)
+
+	assert("getCalibration", testIn, fmt.Sprintf("%s", testOut), fmt.Sprintf("%s", calibration.tokens), t)
+	assert("getCalibration", testIn, "87", fmt.Sprintf("%d", calibration.value), t)
+
+	// custom vocabularies with multi-digit words and overlapping prefixes
+	decoder = newDecoder(extendVocabulary(EnglishVocabulary, Vocabulary{"zero": 0, "twelve": 12, "eins": 1, "neun": 9}))
+
+	testIn = "einszerotwelvex"
+	calibration = getCalibration(decoder, testIn)
+
+	assert("getCalibration", testIn, "eins twelve 112", fmt.Sprintf("%s %s %d", calibration.first.word, calibration.last.word, calibration.value), t)
+
+	testIn = "neun9999"
+	calibration = getCalibration(decoder, testIn)
+
+	assert("getCalibration", testIn, "99", fmt.Sprintf("%d", calibration.value), t)
+}
+
 func TestPart1(t *testing.T) {
 
 	for index, element := range P1_IN_TEST {
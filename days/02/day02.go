@@ -5,19 +5,19 @@ import (
 	"log"
 	"os"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 )
 
 const DAY = "02"
 
-type Color int
+type Color string
 
 const (
-	Red Color = iota + 1
-	Blue
-	Green
-	NotAColor
+	Red   Color = "red"
+	Green Color = "green"
+	Blue  Color = "blue"
 )
 
 type Game struct {
@@ -34,6 +34,13 @@ type BallCount struct {
 	count int
 }
 
+type Violation struct {
+	drawIndex int
+	color     Color
+	count     int
+	limit     int
+}
+
 func (b Game) String() string {
 
 	return fmt.Sprintf("Game(id=%d, draws=%s)", b.id, b.draws)
@@ -46,7 +53,12 @@ func (b Draw) String() string {
 
 func (b BallCount) String() string {
 
-	return fmt.Sprintf("BallCount(count=%d, color=%d)", b.count, b.color)
+	return fmt.Sprintf("BallCount(count=%d, color=%s)", b.count, b.color)
+}
+
+func (b Violation) String() string {
+
+	return fmt.Sprintf("Violation(drawIndex=%d, color=%s, count=%d, limit=%d)", b.drawIndex, b.color, b.count, b.limit)
 }
 
 func parseGame(s string) Game {
@@ -62,7 +74,7 @@ func parseGame(s string) Game {
 
 	var game Game
 
-	gameId, _ := strconv.ParseInt(re.FindString(gameString), 10, 8)
+	gameId, _ := strconv.ParseInt(re.FindString(gameString), 10, 64)
 	game.id = int(gameId)
 
 	for _, drawString := range strings.Split(drawsString, ";") {
@@ -96,7 +108,7 @@ func parseBallCount(s string) BallCount {
 	countString := strings.Split(s, " ")[0]
 	colorString := strings.Split(s, " ")[1]
 
-	count, _ := strconv.ParseInt(countString, 10, 8)
+	count, _ := strconv.ParseInt(countString, 10, 64)
 	countInt := int(count)
 	color := parseColor(colorString)
 
@@ -110,55 +122,97 @@ func parseBallCount(s string) BallCount {
 
 func parseColor(s string) Color {
 
-	s = strings.TrimSpace(s)
+	return Color(strings.TrimSpace(s))
+}
+
+func getColors(games []Game) []Color {
+
+	var colors []Color
+
+	for _, game := range games {
+
+		for _, draw := range game.draws {
+
+			for _, ballCount := range draw.ballCounts {
+
+				if !slices.Contains(colors, ballCount.color) {
+
+					colors = append(colors, ballCount.color)
+				}
+			}
+		}
+	}
+
+	slices.Sort(colors)
+
+	return colors
+}
+
+func isGamePossible(game Game, limits map[Color]int) bool {
+
+	return len(getViolations(game, limits)) == 0
+}
+
+// colors without a limit are not in the bag at all
+func getViolations(game Game, limits map[Color]int) []Violation {
 
-	var result Color
+	var violations []Violation
 
-	switch s {
+	for drawIndex, draw := range game.draws {
 
-	case "red":
-		result = Red
-	case "green":
-		result = Green
-	case "blue":
-		result = Blue
-	default:
-		result = NotAColor
+		for _, ballCount := range draw.ballCounts {
+
+			if ballCount.count > limits[ballCount.color] {
 
+				violations = append(violations, Violation{
+					drawIndex: drawIndex,
+					color:     ballCount.color,
+					count:     ballCount.count,
+					limit:     limits[ballCount.color],
+				})
+			}
+		}
 	}
 
-	return result
+	return violations
 }
 
-func isGamePossible(game Game, limitRed int, limitGreen int, limitBlue int) bool {
+func getMinimalBag(game Game) map[Color]int {
+
+	bag := make(map[Color]int)
 
 	for _, draw := range game.draws {
 
-		if !isDrawPossible(draw, limitRed, limitGreen, limitBlue) {
+		for _, ballCount := range draw.ballCounts {
 
-			return false
+			bag[ballCount.color] = max(bag[ballCount.color], ballCount.count)
 		}
 	}
 
-	return true
+	return bag
 }
 
-func isDrawPossible(draw Draw, limitRed int, limitGreen int, limitBlue int) bool {
+// every bag holding at least these counts is consistent with all games, every other bag is not
+func getMinimalConsistentBag(games []Game) map[Color]int {
+
+	bag := make(map[Color]int)
 
-	for _, ballCount := range draw.ballCounts {
+	for _, game := range games {
 
-		var limit int
+		for color, count := range getMinimalBag(game) {
 
-		switch ballCount.color {
-		case Red:
-			limit = limitRed
-		case Green:
-			limit = limitGreen
-		case Blue:
-			limit = limitBlue
+			bag[color] = max(bag[color], count)
 		}
+	}
+
+	return bag
+}
 
-		if ballCount.count > limit {
+func isBagConsistent(bag map[Color]int, games []Game) bool {
+
+	for _, game := range games {
+
+		if !isGamePossible(game, bag) {
 
 			return false
 		}
@@ -167,51 +221,47 @@ func isDrawPossible(draw Draw, limitRed int, limitGreen int, limitBlue int) bool
 	return true
 }
 
-func getGamePower(game Game) int64 {
+func getGamePower(game Game, colors []Color) int64 {
 
-	var maxRed int64 = 0
-	var maxGreen int64 = 0
-	var maxBlue int64 = 0
+	var power int64 = 1
 
-	for _, draw := range game.draws {
+	bag := getMinimalBag(game)
 
-		for _, ballCount := range draw.ballCounts {
+	for _, color := range colors {
 
-			switch ballCount.color {
-			case Red:
-				maxRed = max(maxRed, int64(ballCount.count))
-			case Green:
-				maxGreen = max(maxGreen, int64(ballCount.count))
-			case Blue:
-				maxBlue = max(maxBlue, int64(ballCount.count))
-			}
-		}
+		power *= int64(bag[color])
 	}
 
-	if maxRed == 0 || maxGreen == 0 || maxBlue == 0 {
+	return power
+}
+
+func parseGames(content string) []Game {
+
+	var games []Game
 
-		return 0
+	for _, line := range strings.Split(content, "\n") {
+
+		games = append(games, parseGame(line))
 	}
 
-	return maxRed * maxGreen * maxBlue
+	return games
 }
 
 func Part1(input string) string {
 
-	limitRed := 12
-	limitGreen := 13
-	limitBlue := 14
+	limits := map[Color]int{
+		Red:   12,
+		Green: 13,
+		Blue:  14,
+	}
 
 	content := GetContent(input)
 
-	lines := strings.Split(content, "\n")
-
 	sum := 0
 
-	for _, line := range lines {
+	for _, game := range parseGames(content) {
 
-		game := parseGame(line)
-		if isGamePossible(game, limitRed, limitGreen, limitBlue) {
+		if isGamePossible(game, limits) {
 
 			sum += game.id
 		}
@@ -224,14 +274,14 @@ func Part2(input string) string {
 
 	content := GetContent(input)
 
-	lines := strings.Split(content, "\n")
+	games := parseGames(content)
+	colors := getColors(games)
 
 	var sum int64 = 0
 
-	for _, line := range lines {
+	for _, game := range games {
 
-		game := parseGame(line)
-		sum += getGamePower(game)
+		sum += getGamePower(game, colors)
 	}
 
 	return strconv.FormatInt(sum, 10)
@@ -14,11 +14,11 @@ var P2_OUT_TEST = [1]string{"2286"}
 func TestParseColor(t *testing.T) {
 
 	testIn := [4]string{"red", "green", "blue", "yellow"}
-	testOut := [4]Color{Red, Green, Blue, NotAColor}
+	testOut := [4]Color{Red, Green, Blue, Color("yellow")}
 
 	for index, test := range testIn {
 
-		assert("parseColor", test, fmt.Sprintf("%d", testOut[index]), fmt.Sprintf("%d", parseColor(test)), t)
+		assert("parseColor", test, string(testOut[index]), string(parseColor(test)), t)
 	}
 }
 
@@ -70,6 +70,24 @@ func TestParseGame(t *testing.T) {
 	assert("parseGame", testIn, fmt.Sprintf("%s", testOut), fmt.Sprintf("%s", gameReceived), t)
 }
 
+func TestBagQueries(t *testing.T) {
+
+	games := parseGames(GetContent(P1_IN_TEST[0]))
+
+	assert("getColors", P1_IN_TEST[0], "[blue green red]", fmt.Sprintf("%v", getColors(games)), t)
+	assert("getMinimalBag", P1_IN_TEST[0], "map[blue:6 green:13 red:20]", fmt.Sprintf("%v", getMinimalBag(games[2])), t)
+	assert("getMinimalConsistentBag", P1_IN_TEST[0], "map[blue:15 green:13 red:20]", fmt.Sprintf("%v", getMinimalConsistentBag(games)), t)
+	assert("isBagConsistent", P1_IN_TEST[0], "true", fmt.Sprintf("%t", isBagConsistent(getMinimalConsistentBag(games), games)), t)
+
+	testIn := parseGame("Game 7: 3 red, 2 purple; 4 purple")
+	testOut := []Violation{{drawIndex: 0, color: "purple", count: 2, limit: 1}, {drawIndex: 1, color: "purple", count: 4, limit: 1}}
+
+	received := getViolations(testIn, map[Color]int{Red: 3, "purple": 1})
+
+	assert("getViolations", fmt.Sprintf("%s", testIn), fmt.Sprintf("%s", testOut), fmt.Sprintf("%s", received), t)
+	assert("getGamePower", fmt.Sprintf("%s", testIn), "12", fmt.Sprintf("%d", getGamePower(testIn, getColors([]Game{testIn}))), t)
+}
+
 func TestPart1(t *testing.T) {
 
 	for index, element := range P1_IN_TEST {
@@ -7,289 +7,167 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
 const DAY = "03"
 
-func getPartNumbers(board []string) []int {
-
-	limitY := len(board) - 1
-	limitX := len(board[0]) - 1
-
-	var partNumbers []int
-
-	re := regexp.MustCompile(`\d+`)
-
-	for indexY, line := range board {
-
-		numberLocations := re.FindAllStringIndex(line, -1)
+type Number struct {
+	value  int
+	y      int
+	startX int
+	endX   int
+}
 
-		for _, numberLocation := range numberLocations {
+type Symbol struct {
+	character rune
+	x         int
+	y         int
+}
 
-			var adjacentFields []string
+type Schematic struct {
+	numbers         []Number
+	symbols         []Symbol
+	numbersBySymbol [][]int
+	symbolsByNumber [][]int
+}
 
-			bounceLeft := false
-			bounceRight := false
+type Combination int
 
-			if numberLocation[0] > 0 {
+const (
+	Sum Combination = iota + 1
+	Product
+)
 
-				bounceLeft = true
-				adjacentFields = append(adjacentFields, string(rune(line[numberLocation[0]-1])))
-			}
+func parseSchematic(board []string) Schematic {
 
-			if numberLocation[1] < limitX {
+	var schematic Schematic
 
-				bounceRight = true
-				adjacentFields = append(adjacentFields, string(rune(line[numberLocation[1]])))
-			}
+	symbolIndices := make(map[[2]int]int)
 
-			if indexY > 0 {
+	re := regexp.MustCompile(`\d+`)
 
-				if bounceLeft {
+	for indexY, line := range board {
 
-					adjacentFields = append(adjacentFields, string(rune(board[indexY-1][numberLocation[0]-1])))
-				}
+		line = strings.TrimRight(line, "\r")
 
-				if bounceRight {
+		for _, numberLocation := range re.FindAllStringIndex(line, -1) {
 
-					adjacentFields = append(adjacentFields, string(rune(board[indexY-1][numberLocation[1]])))
-				}
+			value, err := strconv.Atoi(line[numberLocation[0]:numberLocation[1]])
 
-				for i := numberLocation[0]; i <= numberLocation[1]; i++ {
+			if err != nil {
 
-					adjacentFields = append(adjacentFields, string(rune(board[indexY-1][i])))
-				}
+				panic(err)
 			}
 
-			if indexY < limitY {
-
-				if bounceLeft {
-
-					adjacentFields = append(adjacentFields, string(rune(board[indexY+1][numberLocation[0]-1])))
-				}
-
-				if bounceRight {
-
-					adjacentFields = append(adjacentFields, string(rune(board[indexY+1][numberLocation[1]])))
-				}
-
-				for i := numberLocation[0]; i < numberLocation[1]; i++ {
+			schematic.numbers = append(schematic.numbers, Number{
+				value:  value,
+				y:      indexY,
+				startX: numberLocation[0],
+				endX:   numberLocation[1],
+			})
+		}
 
-					adjacentFields = append(adjacentFields, string(rune(board[indexY+1][i])))
-				}
-			}
+		for indexX, character := range line {
 
-			if containsSymbol(adjacentFields) {
+			if isSymbol(character) {
 
-				partNumbers = append(partNumbers, getNumberAt(board, indexY, numberLocation))
+				symbolIndices[[2]int{indexX, indexY}] = len(schematic.symbols)
+				schematic.symbols = append(schematic.symbols, Symbol{character: character, x: indexX, y: indexY})
 			}
 		}
 	}
 
-	return partNumbers
-}
+	// connect every number with the symbols in its surrounding box
+	schematic.numbersBySymbol = make([][]int, len(schematic.symbols))
+	schematic.symbolsByNumber = make([][]int, len(schematic.numbers))
 
-func containsSymbol(fields []string) bool {
+	for numberIndex, number := range schematic.numbers {
 
-	re := regexp.MustCompile(`\d|\.|\n|\t|\r`)
+		for y := number.y - 1; y <= number.y+1; y++ {
 
-	for _, field := range fields {
+			for x := number.startX - 1; x <= number.endX; x++ {
 
-		if !re.MatchString(fmt.Sprintf("%s", field)) {
+				if symbolIndex, found := symbolIndices[[2]int{x, y}]; found {
 
-			return true
+					schematic.numbersBySymbol[symbolIndex] = append(schematic.numbersBySymbol[symbolIndex], numberIndex)
+					schematic.symbolsByNumber[numberIndex] = append(schematic.symbolsByNumber[numberIndex], symbolIndex)
+				}
+			}
 		}
 	}
 
-	return false
+	return schematic
 }
 
-func getNumberAt(board []string, locY int, locsX []int) int {
-
-	numberString := board[locY][locsX[0]:locsX[1]]
-
-	number, err := strconv.ParseInt(numberString, 10, 32)
+func isSymbol(character rune) bool {
 
-	if err != nil {
-
-		panic(err)
-	}
-
-	return int(number)
+	return character != '.' && !unicode.IsDigit(character) && !unicode.IsSpace(character)
 }
 
-func getGearRatios(board []string) []int64 {
-
-	limitY := len(board) - 1
-	limitX := len(board[0]) - 1
-
-	var gearRatios []int64
-
-	re := regexp.MustCompile(`\*`)
-
-	for indexY, line := range board {
-
-		gearLocations := re.FindAllStringIndex(line, -1)
-
-		for _, gearLocation := range gearLocations {
-
-			var lines []string
-
-			bounceLeft := false
-			bounceRight := false
-
-			if gearLocation[0] > 0 {
-
-				bounceLeft = true
-
-				currentLeft := gearLocation[0] - 1
-
-				for {
-					if (currentLeft <= 0) || (!isNumber(rune(line[currentLeft]))) {
-
-						break
-					}
-					currentLeft--
-				}
-
-				lines = append(lines, line[currentLeft:gearLocation[0]])
-			}
-
-			if gearLocation[0] < limitX {
-
-				bounceRight = true
-
-				currentRight := gearLocation[0] + 1
-
-				for {
-					if (currentRight >= limitX) || (!isNumber(rune(line[currentRight]))) {
-
-						break
-					}
-
-					currentRight++
-				}
-
-				lines = append(lines, line[gearLocation[0]:currentRight])
-			}
-
-			if indexY > 0 {
-
-				currentIndexY := indexY - 1
-
-				currentLeft := gearLocation[0]
-
-				if bounceLeft {
-
-					currentLeft = gearLocation[0] - 1
-
-					for {
-
-						if (currentLeft <= 0) || (!isNumber(rune(board[currentIndexY][currentLeft]))) {
-
-							break
-						}
-
-						currentLeft--
-					}
-				}
-
-				currentRight := gearLocation[0]
-
-				if bounceRight {
-
-					currentRight = gearLocation[0] + 1
-
-					for {
-
-						if (currentRight >= limitX) || (!isNumber(rune(board[currentIndexY][currentRight]))) {
-
-							break
-						}
-						currentRight++
-					}
-				}
-
-				lines = append(lines, board[indexY-1][currentLeft:currentRight])
-			}
-
-			if indexY < limitY {
-
-				currentIndexY := indexY + 1
-
-				currentLeft := gearLocation[0]
-
-				if bounceLeft {
-
-					currentLeft = gearLocation[0] - 1
-
-					for {
-
-						if (currentLeft <= 0) || (!isNumber(rune(board[currentIndexY][currentLeft]))) {
-
-							break
-						}
-
-						currentLeft--
-					}
-				}
-
-				currentRight := gearLocation[0]
+// returns the numbers adjacent to at least one symbol of the class, an empty class matches all symbols
+func getNumbersAdjacentTo(schematic Schematic, symbolClass string) []int {
 
-				if bounceRight {
+	var numbers []int
 
-					currentRight = gearLocation[0] + 1
+	for numberIndex, number := range schematic.numbers {
 
-					for {
+		for _, symbolIndex := range schematic.symbolsByNumber[numberIndex] {
 
-						if (currentRight >= limitX) || (!isNumber(rune(board[currentIndexY][currentRight]))) {
+			if symbolClass == "" || strings.ContainsRune(symbolClass, schematic.symbols[symbolIndex].character) {
 
-							break
-						}
-						currentRight++
-					}
-				}
-
-				lines = append(lines, board[currentIndexY][currentLeft:currentRight])
+				numbers = append(numbers, number.value)
+				break
 			}
+		}
+	}
 
-			joinedLines := strings.Join(lines, "_")
+	return numbers
+}
 
-			reNumbers := regexp.MustCompile(`\d+`)
+// combines the numbers around every symbol that has exactly the given number of neighbours
+func getGearValues(schematic Schematic, symbol rune, neighbours int, combination Combination) []int64 {
 
-			matches := reNumbers.FindAllString(joinedLines, -1)
+	var values []int64
 
-			if len(matches) == 2 {
+	for symbolIndex, numberIndices := range schematic.numbersBySymbol {
 
-				numberOne, err1 := strconv.ParseInt(matches[0], 10, 32)
+		if schematic.symbols[symbolIndex].character != symbol || len(numberIndices) != neighbours {
 
-				if err1 != nil {
+			continue
+		}
 
-					panic(err1)
-				}
+		value := int64(0)
 
-				numberTwo, err2 := strconv.ParseInt(matches[1], 10, 32)
+		if combination == Product {
 
-				if err2 != nil {
+			value = 1
+		}
 
-					panic(err2)
-				}
+		for _, numberIndex := range numberIndices {
 
-				gearRatios = append(gearRatios, numberOne*numberTwo)
+			switch combination {
+			case Sum:
+				value += int64(schematic.numbers[numberIndex].value)
+			case Product:
+				value *= int64(schematic.numbers[numberIndex].value)
 			}
 		}
+
+		values = append(values, value)
 	}
 
-	return gearRatios
+	return values
 }
 
-func isNumber(character rune) bool {
+func getPartNumbers(board []string) []int {
 
-	re := regexp.MustCompile(`\d`)
+	return getNumbersAdjacentTo(parseSchematic(board), "")
+}
 
-	characterString := string(character)
+func getGearRatios(board []string) []int64 {
 
-	return re.MatchString(characterString)
+	return getGearValues(parseSchematic(board), '*', 2, Product)
 }
 
 func Part1(input string) string {
@@ -11,6 +11,25 @@ var P1_OUT_TEST = []string{"4361", "925", "0"}
 var P2_IN_TEST = []string{fmt.Sprintf("test/%s/in01.txt", DAY), fmt.Sprintf("test/%s/in02.txt", DAY), fmt.Sprintf("test/%s/in03.txt", DAY)}
 var P2_OUT_TEST = []string{"467835", "6756", "0"}
 
+func TestSchematicQueries(t *testing.T) {
+
+	testIn := []string{
+		"12.3",
+		"..#*",
+		"4.$5",
+	}
+
+	schematic := parseSchematic(testIn)
+
+	assert("parseSchematic", "numbers", "4", fmt.Sprintf("%d", len(schematic.numbers)), t)
+	assert("parseSchematic", "symbols", "3", fmt.Sprintf("%d", len(schematic.symbols)), t)
+	assert("getNumbersAdjacentTo", "$", "[5]", fmt.Sprintf("%v", getNumbersAdjacentTo(schematic, "$")), t)
+	assert("getNumbersAdjacentTo", "", "[12 3 5]", fmt.Sprintf("%v", getNumbersAdjacentTo(schematic, "")), t)
+	assert("getGearValues", "# with 2 by sum", "[]", fmt.Sprintf("%v", getGearValues(schematic, '#', 2, Sum)), t)
+	assert("getGearValues", "* with 2 by product", "[15]", fmt.Sprintf("%v", getGearValues(schematic, '*', 2, Product)), t)
+	assert("getGearValues", "# with 3 by sum", "[20]", fmt.Sprintf("%v", getGearValues(schematic, '#', 3, Sum)), t)
+}
+
 func TestPart1(t *testing.T) {
 
 	for index, element := range P1_IN_TEST {
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"regexp"
@@ -12,10 +14,11 @@ import (
 
 const DAY = "04"
 
-type CardData struct {
-	card        Card
-	unprocessed int
-	processed   int
+type CardCascade struct {
+	Id              int         `json:"id"`
+	MatchingNumbers []int       `json:"matchingNumbers"`
+	Copies          int         `json:"copies"`
+	WonFrom         map[int]int `json:"wonFrom,omitempty"`
 }
 
 type Card struct {
@@ -123,57 +126,68 @@ func Part1(input string) string {
 	return strconv.FormatInt(totalWorth, 10)
 }
 
-func Part2(input string) string {
+func parseCards(content string) []Card {
 
-	content := GetContent(input)
+	var cards []Card
 
-	lines := strings.Split(content, "\n")
+	for _, line := range strings.Split(content, "\n") {
+
+		cards = append(cards, parseCard(line))
+	}
 
-	cardMap := make(map[int]CardData)
+	return cards
+}
 
-	processingQueue := make([]int, 0)
+// every card only wins copies of later cards, so one forward pass settles all copy counts
+func getCascade(cards []Card, withTree bool) []CardCascade {
 
-	for _, line := range lines {
+	cascade := make([]CardCascade, len(cards))
 
-		card := parseCard(line)
-		cardMap[card.id] = CardData{card: card, unprocessed: 1, processed: 0}
-		processingQueue = append(processingQueue, card.id)
+	for index, card := range cards {
+
+		cascade[index].Id = card.id
+		cascade[index].Copies = 1
 	}
 
-	for {
+	for index, card := range cards {
 
-		if len(processingQueue) == 0 {
+		cascade[index].MatchingNumbers = getMatchingNumbers(card)
 
-			break
-		}
+		for wonIndex := index + 1; wonIndex <= index+len(cascade[index].MatchingNumbers) && wonIndex < len(cards); wonIndex++ {
 
-		cardId := processingQueue[0]
-		processingQueue = processingQueue[1:]
+			cascade[wonIndex].Copies += cascade[index].Copies
 
-		card := cardMap[cardId]
-		matches := len(getMatchingNumbers(card.card))
+			if withTree {
 
-		for i := cardId + 1; i <= cardId+matches; i++ {
+				if cascade[wonIndex].WonFrom == nil {
 
-			matchCard := cardMap[i]
-			matchCard.unprocessed += card.unprocessed
-			if !slices.Contains(processingQueue, i) {
-				processingQueue = append(processingQueue, i)
+					cascade[wonIndex].WonFrom = make(map[int]int)
+				}
+				cascade[wonIndex].WonFrom[card.id] += cascade[index].Copies
 			}
-			cardMap[i] = matchCard
 		}
+	}
 
-		card.processed += card.unprocessed
-		card.unprocessed = 0
+	return cascade
+}
 
-		cardMap[cardId] = card
-	}
+func writeCascadeJSON(writer io.Writer, cascade []CardCascade) error {
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(cascade)
+}
+
+func Part2(input string) string {
+
+	content := GetContent(input)
 
 	var totalSum int64 = 0
 
-	for _, cardData := range cardMap {
+	for _, cardCascade := range getCascade(parseCards(content), false) {
 
-		totalSum += int64(cardData.processed)
+		totalSum += int64(cardCascade.Copies)
 	}
 
 	return strconv.FormatInt(totalSum, 10)
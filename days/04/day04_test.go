@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -32,6 +33,34 @@ func TestWorth(t *testing.T) {
 	assert("getWorth", fmt.Sprintf("%s", testIn), strconv.FormatInt(testOut, 10), strconv.FormatInt(result, 10), t)
 }
 
+func TestCascade(t *testing.T) {
+
+	testIn := P2_IN_TEST[0]
+	testOut := []string{"1 4 1 map[]", "2 2 2 map[1:1]", "3 2 4 map[1:1 2:2]", "4 1 8 map[1:1 2:2 3:4]", "5 0 14 map[1:1 3:4 4:8]", "6 0 1 map[]"}
+
+	cascade := getCascade(parseCards(GetContent(testIn)), true)
+
+	for index, cardCascade := range cascade {
+
+		received := fmt.Sprintf("%d %d %d %v", cardCascade.Id, len(cardCascade.MatchingNumbers), cardCascade.Copies, cardCascade.WonFrom)
+		assert("getCascade", testIn, testOut[index], received, t)
+	}
+
+	var builder strings.Builder
+
+	if err := writeCascadeJSON(&builder, cascade[:2]); err != nil {
+
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(builder.String(), `"wonFrom": {
+      "1": 1
+    }`) {
+
+		t.Errorf("writeCascadeJSON(%s) received unexpected JSON '%s'", testIn, builder.String())
+	}
+}
+
 func TestPart1(t *testing.T) {
 
 	for index, element := range P1_IN_TEST {
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"math"
+	"math/big"
 	"os"
 	"regexp"
 	"strconv"
@@ -45,47 +47,142 @@ func stringToNumber(s string) int64 {
 	return number
 }
 
+type Solver func(race Race) int64
+
 /*
 *
 Explanation:
-Let time be `t`, driving time (cruising) be `c`, pressing time be `p` and distance be `d`.
-Then (1) `t = c + p`, and, since each second pressing time increases speed by 1, (2) `d = c * p`.
-`d` is maximized for `c = p < t`. Since `c` and `p` can be treated interchangeably, we assume `c >= t/2` and multiply our result by 2 in the end.
-First, we calculate `c_max` achieving the given `d` and `t` by plugging in (2) into (1) and solving for `c` yielding `c = 1/2 (sqrt(t^2 - 4 d) + t).
-To win,`c` can take any natural number between `c_max` and `t/2` (assuming `c >= t/2').
-Hence, the total number of possibilities for winning is roughly `(c_max - t/2)*2`. We use rounding for edge cases (when `c_max` is an integer)
-and correct the result by 1 if `t` was even (Where we have the additional possibility that `c = p = t/2').
+Let time be `t`, pressing time be `p` and distance be `d`. We win if `p * (t - p) > d`.
+Substituting `q = 2p - t` yields `q^2 < t^2 - 4d = D`, where `q` has the same parity as `t`.
+Let `m` be the largest integer with `m^2 < D`, derived from the integer square root of `D`.
+Then we count the integers in `[-m, m]` with the parity of `t`, which is `m + 1` if `m` and `t` have the same parity and `m` otherwise.
+Every such `q` satisfies `|q| <= t`, so `p` automatically lies in `[0, t]`.
 */
 func getWinningPossibilities(race Race) int64 {
 
-	maximumDrivingTime := (float64(1) / float64(2)) * (math.Sqrt(math.Pow(float64(race.time), 2)-4*float64(race.distance)) + float64(race.time))
+	// t^2 - 4d must not overflow, otherwise fall back to arbitrary precision
+	if race.time > math.MaxInt32 || race.time < 0 || race.distance < 0 || race.distance > (math.MaxInt64-race.time*race.time)/4 {
+
+		return getWinningPossibilitiesBig(big.NewInt(race.time), big.NewInt(race.distance)).Int64()
+	}
+
+	discriminant := race.time*race.time - 4*race.distance
+
+	if discriminant <= 0 {
+
+		return 0
+	}
+
+	root := integerSqrt(discriminant)
+
+	if root*root == discriminant {
+
+		root--
+	}
+
+	return countWithParity(root, race.time%2)
+}
+
+func getWinningPossibilitiesBig(time *big.Int, distance *big.Int) *big.Int {
+
+	discriminant := new(big.Int).Mul(time, time)
+	discriminant.Sub(discriminant, new(big.Int).Mul(big.NewInt(4), distance))
+
+	if discriminant.Sign() <= 0 {
+
+		return big.NewInt(0)
+	}
+
+	root := new(big.Int).Sqrt(discriminant)
+
+	if new(big.Int).Mul(root, root).Cmp(discriminant) == 0 {
+
+		root.Sub(root, big.NewInt(1))
+	}
+
+	result := new(big.Int).Set(root)
+
+	if root.Bit(0) == time.Bit(0) {
+
+		result.Add(result, big.NewInt(1))
+	}
+
+	return result
+}
+
+func integerSqrt(n int64) int64 {
+
+	root := int64(math.Sqrt(float64(n)))
+
+	// correct the floating point estimate
+	for root*root > n {
+
+		root--
+	}
+
+	for (root+1)*(root+1) <= n {
+
+		root++
+	}
 
-	numberOfPossibilities := (math.Ceil(maximumDrivingTime-float64(1)) - math.Floor(float64(race.time)/float64(2))) * float64(2)
+	return root
+}
+
+func countWithParity(limit int64, parity int64) int64 {
 
-	if race.time%2 == 0 {
+	if limit%2 == parity {
 
-		numberOfPossibilities += 1
+		return limit + 1
 	}
 
-	return int64(numberOfPossibilities)
+	return limit
 }
 
-func parsePart2(input string) Race {
+func getWinningPossibilitiesBruteForce(race Race) int64 {
+
+	var possibilities int64
+
+	for pressingTime := int64(0); pressingTime <= race.time; pressingTime++ {
 
-	var race Race
+		if pressingTime*(race.time-pressingTime) > race.distance {
+
+			possibilities++
+		}
+	}
+
+	return possibilities
+}
+
+// the concatenated numbers may exceed int64
+func parsePart2(input string) (*big.Int, *big.Int) {
 
 	numberRe := regexp.MustCompile(`\d+`)
 	timeString := numberRe.FindString(strings.ReplaceAll(strings.Split(input, "\n")[0], " ", ""))
 	distanceString := numberRe.FindString(strings.ReplaceAll(strings.Split(input, "\n")[1], " ", ""))
 
-	race.time = stringToNumber(timeString)
-	race.distance = stringToNumber(distanceString)
+	time, timeOk := new(big.Int).SetString(timeString, 10)
+	distance, distanceOk := new(big.Int).SetString(distanceString, 10)
+
+	if !timeOk || !distanceOk {
+
+		panic(fmt.Sprintf("invalid race '%s' '%s'", timeString, distanceString))
+	}
 
-	return race
+	return time, distance
 }
 
 func Part1(input string) string {
 
+	return solvePart1(input, getWinningPossibilities)
+}
+
+func Part2(input string) string {
+
+	return solvePart2(input, getWinningPossibilities)
+}
+
+func solvePart1(input string, solver Solver) string {
+
 	content := GetContent(input)
 
 	races := parse(content)
@@ -94,20 +191,25 @@ func Part1(input string) string {
 
 	for _, race := range races {
 
-		possibilities := getWinningPossibilities(race)
+		possibilities := solver(race)
 		result *= possibilities
 	}
 
 	return fmt.Sprintf("%d", result)
 }
 
-func Part2(input string) string {
+func solvePart2(input string, solver Solver) string {
 
 	content := GetContent(input)
 
-	race := parsePart2(content)
+	time, distance := parsePart2(content)
 
-	possibilities := getWinningPossibilities(race)
+	if !time.IsInt64() || !distance.IsInt64() {
+
+		return getWinningPossibilitiesBig(time, distance).String()
+	}
+
+	possibilities := solver(Race{time: time.Int64(), distance: distance.Int64()})
 
 	return fmt.Sprintf("%d", possibilities)
 }
@@ -125,6 +227,16 @@ func GetContent(filepath string) string {
 
 func main() {
 
-	fmt.Println(fmt.Sprintf("Part 1: %s", Part1(fmt.Sprintf("input/%s/in.txt", DAY))))
-	fmt.Println(fmt.Sprintf("Part 2: %s", Part2(fmt.Sprintf("input/%s/in.txt", DAY))))
+	bruteForce := flag.Bool("bruteforce", false, "try every pressing time instead of solving exactly")
+	flag.Parse()
+
+	var solver Solver = getWinningPossibilities
+
+	if *bruteForce {
+
+		solver = getWinningPossibilitiesBruteForce
+	}
+
+	fmt.Println(fmt.Sprintf("Part 1: %s", solvePart1(fmt.Sprintf("input/%s/in.txt", DAY), solver)))
+	fmt.Println(fmt.Sprintf("Part 2: %s", solvePart2(fmt.Sprintf("input/%s/in.txt", DAY), solver)))
 }
@@ -2,14 +2,47 @@ package main
 
 import (
 	"fmt"
+	"math/big"
+	"math/rand"
 	"testing"
 )
 
 var P1_IN_TEST = []string{fmt.Sprintf("test/%s/in01.txt", DAY)}
 var P1_OUT_TEST = []string{"288"}
 
-var P2_IN_TEST = []string{fmt.Sprintf("test/%s/in01.txt", DAY)}
-var P2_OUT_TEST = []string{"71503"}
+var P2_IN_TEST = []string{fmt.Sprintf("test/%s/in01.txt", DAY), fmt.Sprintf("test/%s/in02.txt", DAY), fmt.Sprintf("test/%s/in03.txt", DAY)}
+var P2_OUT_TEST = []string{"71503", "9999999999999999999", "0"}
+
+func TestWinningPossibilitiesAgainstBruteForce(t *testing.T) {
+
+	random := rand.New(rand.NewSource(6))
+
+	for i := 0; i < 2000; i++ {
+
+		time := random.Int63n(500)
+		race := Race{time: time, distance: random.Int63n(time*time/4 + 10)}
+
+		expected := fmt.Sprintf("%d", getWinningPossibilitiesBruteForce(race))
+		assert("getWinningPossibilities", fmt.Sprintf("%v", race), expected, fmt.Sprintf("%d", getWinningPossibilities(race)), t)
+		assert("getWinningPossibilitiesBig", fmt.Sprintf("%v", race), expected, getWinningPossibilitiesBig(big.NewInt(race.time), big.NewInt(race.distance)).String(), t)
+	}
+}
+
+func TestWinningPossibilitiesLarge(t *testing.T) {
+
+	// every pressing time but the extremes wins, p * (t - p) for p = 1 is t - 1
+	testIn := Race{time: 10000000000, distance: 10000000000 - 2}
+	testOut := "9999999999"
+
+	assert("getWinningPossibilities", fmt.Sprintf("%v", testIn), testOut, fmt.Sprintf("%d", getWinningPossibilities(testIn)), t)
+
+	// 4d overflows an int64 while t^2 does not
+	testIn = Race{time: 100, distance: 3000000000000000000}
+	testOut = fmt.Sprintf("%d", getWinningPossibilitiesBruteForce(testIn))
+
+	assert("getWinningPossibilities", fmt.Sprintf("%v", testIn), testOut, fmt.Sprintf("%d", getWinningPossibilities(testIn)), t)
+	assert("getWinningPossibilities", fmt.Sprintf("%v", testIn), "0", testOut, t)
+}
 
 func TestPart1(t *testing.T) {
 
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"regexp"
@@ -9,39 +11,20 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"text/tabwriter"
 )
 
 const DAY = "07"
 
+// hand types depend on the ruleset, so hands only hold their cards and bid
 type Hand struct {
 	bid       int64
-	handType  HandType
 	stringRep string
 }
 
 func (b Hand) String() string {
 
-	return fmt.Sprintf("Hand(bid=%d, handType=%d, stringRep=%s)", b.bid, b.handType, b.stringRep)
-}
-
-var CardValues = map[rune]int{
-	'2': 2,
-	'3': 3,
-	'4': 4,
-	'5': 5,
-	'6': 6,
-	'7': 7,
-	'8': 8,
-	'9': 9,
-	'T': 10,
-	'J': 11,
-	'Q': 12,
-	'K': 13,
-	'A': 14,
-}
-
-var AllCardTypesExceptJoker = []string{
-	"2", "3", "4", "5", "6", "7", "8", "9", "T", "Q", "K", "A",
+	return fmt.Sprintf("Hand(bid=%d, stringRep=%s)", b.bid, b.stringRep)
 }
 
 type HandType int
@@ -57,19 +40,72 @@ const (
 	FiveOfAKind
 )
 
-func parseAllHands(input string, allowJoker bool) []Hand {
+type HandAnalysis struct {
+	hand         Hand
+	substitution string
+	handType     HandType
+	rank         int
+	winnings     int64
+}
+
+var HandTypeNames = map[HandType]string{
+	WTFisThisCard: "unknown",
+	HighCard:      "high card",
+	OnePair:       "one pair",
+	TwoPair:       "two pair",
+	ThreeOfAKind:  "three of a kind",
+	FullHouse:     "full house",
+	FourOfAKind:   "four of a kind",
+	FiveOfAKind:   "five of a kind",
+}
+
+type TieBreak int
+
+const (
+	CardByCard TieBreak = iota + 1
+	HighestCardFirst
+)
+
+type Ruleset struct {
+	name      string
+	cardOrder string
+	wildcards string
+	tieBreak  TieBreak
+}
+
+var StandardRules = Ruleset{
+	name:      "standard",
+	cardOrder: "23456789TJQKA",
+	tieBreak:  CardByCard,
+}
+
+var JokersLowRules = Ruleset{
+	name:      "jokers low",
+	cardOrder: "J23456789TQKA",
+	wildcards: "J",
+	tieBreak:  CardByCard,
+}
+
+var JokersHighRules = Ruleset{
+	name:      "jokers high",
+	cardOrder: "23456789TQKAJ",
+	wildcards: "J",
+	tieBreak:  CardByCard,
+}
+
+func parseAllHands(input string) []Hand {
 
 	var hands []Hand
 
 	for _, line := range strings.Split(input, "\n") {
 
-		hands = append(hands, parseHand(line, allowJoker))
+		hands = append(hands, parseHand(line))
 	}
 
 	return hands
 }
 
-func parseHand(input string, allowJoker bool) Hand {
+func parseHand(input string) Hand {
 
 	var hand Hand
 
@@ -77,215 +113,240 @@ func parseHand(input string, allowJoker bool) Hand {
 
 	hand.bid = stringToNumber(numberRe.FindString(strings.Split(input, " ")[1]))
 	hand.stringRep = strings.Split(input, " ")[0]
-	hand.handType = parseHandType(hand.stringRep, allowJoker)
 
 	return hand
 }
 
-func parseHandType(input string, allowJoker bool) HandType {
-
-	handString := input
-	charMap := getCharOccurrences(handString)
-	charValues := getValues(charMap)
-
-	if !allowJoker || !strings.Contains(handString, "J") {
-
-		if isFiveOfAKind(charMap) {
-			return FiveOfAKind
-		} else if isFourOfAKind(charValues) {
-			return FourOfAKind
-		} else if isFullHouse(charValues) {
-			return FullHouse
-		} else if isThreeOfAKind(charValues) {
-			return ThreeOfAKind
-		} else if isTwoPair(charValues) {
-			return TwoPair
-		} else if isOnePair(charValues) {
-			return OnePair
-		} else if isHighCard(charValues) {
-			return HighCard
-		} else {
-			return WTFisThisCard
-		}
-	}
+func parseHandType(input string, ruleset Ruleset) HandType {
 
-	if charMap['J'] >= 4 || len(charValues) == 2 {
-		return FiveOfAKind
-	} else if charMap['J'] == 3 {
-		return FourOfAKind
-	} else if charMap['J'] == 2 {
+	return getHandType(getSignature(input, ruleset))
+}
 
-		var highestHandType HandType
-		for _, firstJoker := range AllCardTypesExceptJoker {
+// returns the card counts in descending order, wildcards join the most frequent card
+func getSignature(input string, ruleset Ruleset) []int {
 
-			for _, secondJoker := range AllCardTypesExceptJoker {
-				highestHandType = max(highestHandType, parseHandType(strings.Replace(strings.Replace(handString, "J", firstJoker, 1), "J", secondJoker, 1), false))
-			}
-		}
+	charMap := getCharOccurrences(input, ruleset)
+	wildcards := len(input) - getLength(charMap)
+	signature := getValues(charMap)
 
-		return highestHandType
+	if len(signature) == 0 {
 
-	} else if charMap['J'] == 1 {
+		return []int{wildcards}
+	}
 
-		var highestHandType HandType
-		for _, joker := range AllCardTypesExceptJoker {
+	signature[0] += wildcards
 
-			highestHandType = max(highestHandType, parseHandType(strings.Replace(handString, "J", joker, -1), false))
-		}
+	return signature
+}
+
+// hands of any size are typed by their two largest card counts, which orders them like their signatures
+func getHandType(signature []int) HandType {
+
+	top, second := signature[0], 0
 
-		return highestHandType
+	if len(signature) > 1 {
+
+		second = signature[1]
+	}
+
+	switch {
+	case top >= 5:
+		return FiveOfAKind
+	case top == 4:
+		return FourOfAKind
+	case top == 3 && second >= 2:
+		return FullHouse
+	case top == 3:
+		return ThreeOfAKind
+	case top == 2 && second == 2:
+		return TwoPair
+	case top == 2:
+		return OnePair
+	case top == 1:
+		return HighCard
 	}
 
 	return WTFisThisCard
 }
 
-func isHighCard(values []int) bool {
+func getLength(m map[rune]int) int {
+
+	length := 0
+
+	for _, value := range m {
+		length += value
+	}
 
-	return len(values) == 5
+	return length
 }
 
-func isOnePair(values []int) bool {
+func getValues(m map[rune]int) []int {
 
-	if len(values) == 4 {
+	list := make([]int, 0, len(m))
 
-		return true
+	for _, value := range m {
+		list = append(list, value)
 	}
-	return false
-}
 
-func isTwoPair(values []int) bool {
+	sort.Slice(list, func(i, j int) bool {
+		return list[i] > list[j]
+	})
 
-	if len(values) == 3 {
+	return list
+}
 
-		if values[0] == 2 && values[1] == 2 {
+func getCardValue(card rune, ruleset Ruleset) int {
 
-			return true
-		}
-	}
-	return false
+	return strings.IndexRune(ruleset.cardOrder, card)
 }
 
-func isThreeOfAKind(values []int) bool {
+func getTieBreakValues(hand Hand, ruleset Ruleset) []int {
 
-	if len(values) == 3 {
+	var values []int
 
-		if slices.Contains(values, 3) && slices.Contains(values, 1) {
+	for _, card := range hand.stringRep {
 
-			return true
-		}
+		values = append(values, getCardValue(card, ruleset))
 	}
-	return false
+
+	if ruleset.tieBreak == HighestCardFirst {
+
+		sort.Sort(sort.Reverse(sort.IntSlice(values)))
+	}
+
+	return values
 }
 
-func isFullHouse(values []int) bool {
+func sortHands(hands []Hand, ruleset Ruleset) []Hand {
 
-	if len(values) == 2 {
+	var newHands []Hand = make([]Hand, len(hands))
 
-		if slices.Contains(values, 3) {
+	copy(newHands, hands)
 
-			return true
-		}
-	}
-	return false
-}
+	sort.SliceStable(newHands, func(i, j int) bool {
+		return isFirstSmallerThanSecond(newHands[i], newHands[j], ruleset)
+	})
 
-func isFourOfAKind(charValues []int) bool {
+	return newHands
+}
 
-	if len(charValues) == 2 {
+// the signatures come from the given ruleset, not from the one the hands were parsed with
+func isFirstSmallerThanSecond(first Hand, second Hand, ruleset Ruleset) bool {
 
-		if slices.Contains(charValues, 4) {
+	// signatures compare lexicographically in the same order as the hand types
+	if comparison := slices.Compare(getSignature(first.stringRep, ruleset), getSignature(second.stringRep, ruleset)); comparison != 0 {
 
-			return true
-		}
+		return comparison < 0
 	}
-	return false
+
+	return slices.Compare(getTieBreakValues(first, ruleset), getTieBreakValues(second, ruleset)) < 0
 }
 
-func isFiveOfAKind(charMap map[rune]int) bool {
+// replaces all wildcards by the most frequent other card, preferring higher cards on ties
+func getBestSubstitution(input string, ruleset Ruleset) string {
 
-	return len(charMap) == 1
-}
+	if ruleset.wildcards == "" {
 
-func getValues(m map[rune]int) []int {
+		return input
+	}
 
-	list := make([]int, 0, len(m))
+	var bestCard rune
+	bestCount := 0
+	charMap := getCharOccurrences(input, ruleset)
 
-	for _, value := range m {
-		list = append(list, value)
+	for _, card := range ruleset.cardOrder {
+
+		if !strings.ContainsRune(ruleset.wildcards, card) && charMap[card] >= bestCount {
+
+			bestCard = card
+			bestCount = charMap[card]
+		}
 	}
 
-	sort.Slice(list, func(i, j int) bool {
-		return list[i] > list[j]
-	})
+	return strings.Map(func(card rune) rune {
 
-	return list
+		if strings.ContainsRune(ruleset.wildcards, card) {
+
+			return bestCard
+		}
+		return card
+	}, input)
 }
 
-func getCharOccurrences(input string) map[rune]int {
+func getCharOccurrences(input string, ruleset Ruleset) map[rune]int {
 
 	result := map[rune]int{}
 	for _, char := range input {
-		result[char] += 1
+		if !strings.ContainsRune(ruleset.wildcards, char) {
+			result[char] += 1
+		}
 	}
 
 	return result
 }
 
-func sortHands(hands []Hand, allowJoker bool) []Hand {
+func analyseHands(hands []Hand, ruleset Ruleset) []HandAnalysis {
 
-	var newHands []Hand = make([]Hand, len(hands))
+	var analyses []HandAnalysis
 
-	copy(newHands, hands)
+	for index, hand := range sortHands(hands, ruleset) {
 
-	sort.Slice(newHands, func(i, j int) bool {
-		return isFirstSmallerThanSecond(newHands[i], newHands[j], allowJoker)
-	})
+		rank := index + 1
 
-	return newHands
+		analyses = append(analyses, HandAnalysis{
+			hand:         hand,
+			substitution: getBestSubstitution(hand.stringRep, ruleset),
+			handType:     parseHandType(hand.stringRep, ruleset),
+			rank:         rank,
+			winnings:     int64(rank) * hand.bid,
+		})
+	}
+
+	return analyses
 }
 
-func isFirstSmallerThanSecond(first Hand, second Hand, allowJoker bool) bool {
+func getTotalWinnings(analyses []HandAnalysis) int64 {
 
-	if first.handType < second.handType {
+	var result int64
 
-		return true
-	} else if first.handType > second.handType {
+	for _, analysis := range analyses {
 
-		return false
+		result += analysis.winnings
 	}
 
-	if allowJoker {
-		CardValues['J'] = 1
-	}
+	return result
+}
+
+func writeExplanation(writer io.Writer, analyses []HandAnalysis, ruleset Ruleset) error {
+
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(table, "Rules: %s\n", ruleset.name)
+	fmt.Fprintln(table, "Rank\tHand\tSubstitution\tType\tBid\tWinnings")
 
-	for index := range first.stringRep {
+	for _, analysis := range analyses {
 
-		if CardValues[rune(first.stringRep[index])] < CardValues[rune(second.stringRep[index])] {
+		substitution := analysis.substitution
 
-			return true
-		} else if CardValues[rune(first.stringRep[index])] > CardValues[rune(second.stringRep[index])] {
+		if substitution == analysis.hand.stringRep {
 
-			return false
+			substitution = "-"
 		}
+
+		fmt.Fprintf(table, "%d\t%s\t%s\t%s\t%d\t%d\n", analysis.rank, analysis.hand.stringRep, substitution, HandTypeNames[analysis.handType], analysis.hand.bid, analysis.winnings)
 	}
 
-	panic(fmt.Sprintf("Cards %s and %s are identical", first.stringRep, second.stringRep))
+	fmt.Fprintf(table, "Total\t\t\t\t\t%d\n", getTotalWinnings(analyses))
+
+	return table.Flush()
 }
 
 func Part1(input string) string {
 
 	content := GetContent(input)
 
-	hands := parseAllHands(content, false)
-	hands = sortHands(hands, false)
-
-	var result int64
-
-	for index, hand := range hands {
-
-		rank := index + 1
-		result += int64(rank) * hand.bid
-	}
+	hands := parseAllHands(content)
+	result := getTotalWinnings(analyseHands(hands, StandardRules))
 
 	return strconv.FormatInt(result, 10)
 }
@@ -294,16 +355,8 @@ func Part2(input string) string {
 
 	content := GetContent(input)
 
-	hands := parseAllHands(content, true)
-	hands = sortHands(hands, true)
-
-	var result int64
-
-	for index, hand := range hands {
-
-		rank := index + 1
-		result += int64(rank) * hand.bid
-	}
+	hands := parseAllHands(content)
+	result := getTotalWinnings(analyseHands(hands, JokersLowRules))
 
 	return strconv.FormatInt(result, 10)
 }
@@ -331,8 +384,30 @@ func stringToNumber(s string) int64 {
 	return number
 }
 
+func explain(input string) {
+
+	content := GetContent(input)
+
+	for _, ruleset := range []Ruleset{StandardRules, JokersLowRules} {
+
+		if err := writeExplanation(os.Stdout, analyseHands(parseAllHands(content), ruleset), ruleset); err != nil {
+
+			log.Fatal(err)
+		}
+		fmt.Println()
+	}
+}
+
 func main() {
 
+	explainFlag := flag.Bool("explain", false, "print the sorted hands with substitutions, ranks and winnings")
+	flag.Parse()
+
+	if *explainFlag {
+
+		explain(fmt.Sprintf("input/%s/in.txt", DAY))
+	}
+
 	fmt.Println(fmt.Sprintf("Part 1: %s", Part1(fmt.Sprintf("input/%s/in.txt", DAY))))
 	fmt.Println(fmt.Sprintf("Part 2: %s", Part2(fmt.Sprintf("input/%s/in.txt", DAY))))
 }
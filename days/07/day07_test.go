@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -14,9 +15,9 @@ var P2_OUT_TEST = []string{"5905"}
 func TestParseHand(t *testing.T) {
 
 	testIn := "32T3K 765"
-	testOut := Hand{bid: 765, stringRep: "32T3K", handType: OnePair}
+	testOut := Hand{bid: 765, stringRep: "32T3K"}
 
-	result := parseHand(testIn, false)
+	result := parseHand(testIn)
 
 	assert("parseHand", testIn, fmt.Sprintf("%s", testOut), fmt.Sprintf("%s", result), t)
 }
@@ -28,7 +29,89 @@ func TestParseHandType(t *testing.T) {
 
 	for index := range testIn {
 
-		assert("parseHandType", testIn[index], fmt.Sprintf("%d", testOut[index]), fmt.Sprintf("%d", parseHandType(testIn[index], false)), t)
+		assert("parseHandType", testIn[index], fmt.Sprintf("%d", testOut[index]), fmt.Sprintf("%d", parseHandType(testIn[index], StandardRules)), t)
+	}
+}
+
+func TestRulesets(t *testing.T) {
+
+	testIn := []string{"QJJQ2", "JJJJJ", "T55J5", "KTJJT"}
+	testOut := []HandType{FourOfAKind, FiveOfAKind, FourOfAKind, FourOfAKind}
+
+	for index := range testIn {
+
+		assert("parseHandType", testIn[index], fmt.Sprintf("%d", testOut[index]), fmt.Sprintf("%d", parseHandType(testIn[index], JokersLowRules)), t)
+	}
+
+	hands := parseAllHands("JKKK2 1\nQQQQ2 2")
+
+	assert("sortHands", "jokers low", "JKKK2", sortHands(hands, JokersLowRules)[0].stringRep, t)
+	assert("sortHands", "jokers high", "QQQQ2", sortHands(hands, JokersHighRules)[0].stringRep, t)
+
+	// signatures compare hands of any size
+	hands = sortHands(parseAllHands("AKQ 1\n223 2\n777 3"), StandardRules)
+
+	assert("sortHands", "three cards", "AKQ 223 777", fmt.Sprintf("%s %s %s", hands[0].stringRep, hands[1].stringRep, hands[2].stringRep), t)
+
+	// the same hands rank differently under different rulesets
+	hands = parseAllHands("JKKK2 1\nQQQ22 2")
+
+	assert("sortHands", "standard", "JKKK2 QQQ22", fmt.Sprintf("%s %s", sortHands(hands, StandardRules)[0].stringRep, sortHands(hands, StandardRules)[1].stringRep), t)
+	assert("sortHands", "jokers low", "QQQ22 JKKK2", fmt.Sprintf("%s %s", sortHands(hands, JokersLowRules)[0].stringRep, sortHands(hands, JokersLowRules)[1].stringRep), t)
+
+	// comparing the highest cards first ranks the ace above the leading two
+	highestCardFirstRules := StandardRules
+	highestCardFirstRules.tieBreak = HighestCardFirst
+	hands = parseAllHands("2AKQJ 1\n3456K 2")
+
+	assert("sortHands", "card by card", "2AKQJ", sortHands(hands, StandardRules)[0].stringRep, t)
+	assert("sortHands", "highest card first", "3456K", sortHands(hands, highestCardFirstRules)[0].stringRep, t)
+
+	testIn = []string{"777", "AKQ", "22J", "2233445", "AAAAAA2", "JJ"}
+	testOut = []HandType{ThreeOfAKind, HighCard, ThreeOfAKind, TwoPair, FiveOfAKind, OnePair}
+
+	for index := range testIn {
+
+		assert("parseHandType", testIn[index], HandTypeNames[testOut[index]], HandTypeNames[parseHandType(testIn[index], JokersLowRules)], t)
+	}
+
+	// the rulesets do not share state, so their order must not matter
+	for index, element := range P2_IN_TEST {
+
+		assert("Part2", element, P2_OUT_TEST[index], Part2(element), t)
+		assert("Part1", element, P1_OUT_TEST[index], Part1(element), t)
+	}
+}
+
+func TestAnalyseHands(t *testing.T) {
+
+	testIn := P2_IN_TEST[0]
+	testOut := []string{
+		"1 32T3K 32T3K 3 765",
+		"2 KK677 KK677 4 56",
+		"3 T55J5 T5555 7 2052",
+		"4 QQQJA QQQQA 7 1932",
+		"5 KTJJT KTTTT 7 1100",
+	}
+
+	analyses := analyseHands(parseAllHands(GetContent(testIn)), JokersLowRules)
+
+	for index, analysis := range analyses {
+
+		received := fmt.Sprintf("%d %s %s %d %d", analysis.rank, analysis.hand.stringRep, analysis.substitution, analysis.handType, analysis.winnings)
+		assert("analyseHands", testIn, testOut[index], received, t)
+	}
+
+	var builder strings.Builder
+
+	if err := writeExplanation(&builder, analyses, JokersLowRules); err != nil {
+
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(builder.String(), "KTTTT") || !strings.HasSuffix(strings.TrimSpace(builder.String()), "5905") {
+
+		t.Errorf("writeExplanation(%s) received unexpected table '%s'", testIn, builder.String())
 	}
 }
 
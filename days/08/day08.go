@@ -1,8 +1,12 @@
 package main
 
 import (
+	"days/24/cycle"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"math/big"
 	"os"
 	"slices"
 	"strconv"
@@ -14,17 +18,28 @@ const DAY = "08"
 type State struct {
 	commandIndex int64
 	position     string
-	loopPosition int
-	loopSequence []int64
-	offset       int64
-	steps        int64
 }
 
 func (b State) String() string {
 
-	return fmt.Sprintf("State(commandIndex=%d, position=%s, loopPosition=%d, loopSequence=%#v, offset=%d, steps=%d)", b.commandIndex, b.position, b.loopPosition, b.loopSequence, b.offset, b.steps)
+	return fmt.Sprintf("State(commandIndex=%d, position=%s)", b.commandIndex, b.position)
 }
 
+type Ghost struct {
+	start        string
+	preCycleHits []int64
+	cycleHits    []int64
+	cycleStart   int64
+	cycleLength  int64
+}
+
+func (b Ghost) String() string {
+
+	return fmt.Sprintf("Ghost(start=%s, preCycleHits=%#v, cycleHits=%#v, cycleStart=%d, cycleLength=%d)", b.start, b.preCycleHits, b.cycleHits, b.cycleStart, b.cycleLength)
+}
+
+var errNeverAligns = errors.New("ghosts never align")
+
 type Tuple struct {
 	left  string
 	right string
@@ -87,88 +102,286 @@ func transition(state State, game Game) State {
 	return newState
 }
 
-func getOffsetAndLoop(state State, game Game) (int64, []int64) {
+func getStateKey(state State, game Game) string {
 
-	steps := int64(0)
+	return fmt.Sprintf("%s:%d", state.position, state.commandIndex%int64(len(game.commands)))
+}
 
-	var finishPositions []string
-	var finishCommandIndex []int64
-	var finishSteps []int64
+func analyseGhost(start string, game Game) Ghost {
 
-	for {
+	state := State{position: start, commandIndex: 0}
+
+	step := func(current State) State {
+
+		return transition(current, game)
+	}
+
+	stateCycle := cycle.Find(state, step, cycle.ByKey(func(current State) string {
+
+		return getStateKey(current, game)
+	}))
+
+	ghost := Ghost{
+		start:       start,
+		cycleStart:  int64(stateCycle.Start),
+		cycleLength: int64(stateCycle.Length),
+	}
+
+	// collect the finish positions reached before and during the first pass through the cycle
+	for steps := 0; steps < stateCycle.Start+stateCycle.Length; steps++ {
 
 		if rune(state.position[2]) == 'Z' {
 
-			if slices.Contains(finishPositions, state.position) {
+			if steps < stateCycle.Start {
+
+				ghost.preCycleHits = append(ghost.preCycleHits, int64(steps))
+			} else {
+
+				ghost.cycleHits = append(ghost.cycleHits, int64(steps))
+			}
+		}
+		state = step(state)
+	}
+
+	return ghost
+}
+
+func isGhostOnFinish(ghost Ghost, steps int64) bool {
+
+	if steps < ghost.cycleStart {
+
+		return slices.Contains(ghost.preCycleHits, steps)
+	}
+
+	return slices.Contains(ghost.cycleHits, ghost.cycleStart+(steps-ghost.cycleStart)%ghost.cycleLength)
+}
+
+// combines x = a1 mod m1 and x = a2 mod m2 for moduli that need not be coprime
+func combineCongruences(a1 *big.Int, m1 *big.Int, a2 *big.Int, m2 *big.Int) (*big.Int, *big.Int, bool) {
+
+	g := new(big.Int).GCD(nil, nil, m1, m2)
+	difference := new(big.Int).Sub(a2, a1)
+
+	if new(big.Int).Mod(difference, g).Sign() != 0 {
+
+		return nil, nil, false
+	}
+
+	reducedM1 := new(big.Int).Quo(m1, g)
+	reducedM2 := new(big.Int).Quo(m2, g)
+	lcm := new(big.Int).Mul(reducedM1, m2)
+
+	k := big.NewInt(0)
+
+	if reducedM2.Cmp(big.NewInt(1)) != 0 {
+
+		k.Quo(difference, g)
+		k.Mul(k, new(big.Int).ModInverse(reducedM1, reducedM2))
+		k.Mod(k, reducedM2)
+	}
+
+	x := new(big.Int).Mul(m1, k)
+	x.Add(x, a1)
+	x.Mod(x, lcm)
+
+	return x, lcm, true
+}
+
+// returns the smallest x >= lowerBound with x = residues[i] mod moduli[i] for all i
+func solveCongruences(residues []int64, moduli []int64, lowerBound int64) (*big.Int, bool) {
+
+	x := big.NewInt(0)
+	modulus := big.NewInt(1)
 
-				index := indexOfFirst(finishPositions, finishCommandIndex, state.position, state.commandIndex)
+	for index := range residues {
 
-				if index != -1 {
+		var solvable bool
+		x, modulus, solvable = combineCongruences(x, modulus, big.NewInt(residues[index]), big.NewInt(moduli[index]))
 
-					var loop []int64
+		if !solvable {
 
-					for finishStepIndex := index + 1; finishStepIndex < len(finishSteps); finishStepIndex++ {
+			return nil, false
+		}
+	}
+
+	bound := big.NewInt(lowerBound)
+
+	if x.Cmp(bound) < 0 {
+
+		// x + ceil((bound - x) / modulus) * modulus
+		missing := new(big.Int).Sub(bound, x)
+		missing.Add(missing, modulus)
+		missing.Sub(missing, big.NewInt(1))
+		missing.Quo(missing, modulus)
+		x.Add(x, missing.Mul(missing, modulus))
+	}
+
+	return x, true
+}
 
-						loop = append(loop, finishSteps[finishStepIndex]-finishSteps[finishStepIndex-1])
-					}
+func getAlignedSteps(ghosts []Ghost) (*big.Int, error) {
 
-					loop = append(loop, steps-finishSteps[len(finishSteps)-1])
+	var best *big.Int
 
-					return finishSteps[index], loop
+	// every alignment before some ghost entered its cycle is a pre-cycle hit of that ghost
+	for _, ghost := range ghosts {
+
+		for _, hit := range ghost.preCycleHits {
+
+			aligned := true
+
+			for _, other := range ghosts {
+
+				if !isGhostOnFinish(other, hit) {
+
+					aligned = false
+					break
 				}
 			}
 
-			finishPositions = append(finishPositions, state.position)
-			finishCommandIndex = append(finishCommandIndex, state.commandIndex)
-			finishSteps = append(finishSteps, steps)
+			if aligned && (best == nil || big.NewInt(hit).Cmp(best) < 0) {
+
+				best = big.NewInt(hit)
+			}
+		}
+	}
+
+	// all later alignments solve one congruence per ghost for some combination of cycle hits
+	lowerBound := int64(0)
+
+	for _, ghost := range ghosts {
+
+		lowerBound = max(lowerBound, ghost.cycleStart)
+	}
+
+	residues := make([]int64, len(ghosts))
+	moduli := make([]int64, len(ghosts))
+
+	var combine func(ghostIndex int)
+	combine = func(ghostIndex int) {
+
+		if ghostIndex == len(ghosts) {
+
+			if steps, solvable := solveCongruences(residues, moduli, lowerBound); solvable && (best == nil || steps.Cmp(best) < 0) {
+
+				best = steps
+			}
+			return
 		}
-		state = transition(state, game)
-		steps++
+
+		for _, hit := range ghosts[ghostIndex].cycleHits {
+
+			residues[ghostIndex] = hit
+			moduli[ghostIndex] = ghosts[ghostIndex].cycleLength
+			combine(ghostIndex + 1)
+		}
+	}
+
+	combine(0)
+
+	if best == nil {
+
+		return nil, errNeverAligns
 	}
+
+	return best, nil
 }
 
-func indexOfFirst(stringSlice []string, intSlice []int64, s string, i int64) int {
+func tracePath(start string, game Game, steps int) []string {
 
-	for index, elem := range stringSlice {
+	state := State{position: start, commandIndex: 0}
+	path := []string{start}
 
-		if elem == s && intSlice[index] == i {
+	for i := 0; i < steps; i++ {
 
-			return index
-		}
+		state = transition(state, game)
+		path = append(path, state.position)
 	}
 
-	return -1
+	return path
 }
 
-func areStatesAlignedAndSteps(states []State) (bool, int64) {
+var dotCycleColors = []string{"blue", "darkorange", "purple", "deeppink", "darkgreen", "brown"}
+
+func writeDot(writer io.Writer, game Game) error {
+
+	var nodes []string
+
+	for node := range game.tuples {
+
+		nodes = append(nodes, node)
+	}
+
+	slices.Sort(nodes)
 
-	steps := states[0].steps
+	// mark the edges every ghost runs through during its cycle
+	cycleEdgeColors := make(map[string]string)
+	ghostIndex := 0
 
-	for _, state := range states[1:] {
+	for _, node := range nodes {
 
-		if state.steps != steps {
+		if rune(node[2]) != 'A' {
 
-			return false, -1
+			continue
+		}
+
+		ghost := analyseGhost(node, game)
+		path := tracePath(node, game, int(ghost.cycleStart+ghost.cycleLength))
+		color := dotCycleColors[ghostIndex%len(dotCycleColors)]
+		ghostIndex++
+
+		for step := int(ghost.cycleStart); step < len(path)-1; step++ {
+
+			key := fmt.Sprintf("%s %c %s", path[step], game.commands[step%len(game.commands)], path[step+1])
+
+			if _, marked := cycleEdgeColors[key]; !marked {
+
+				cycleEdgeColors[key] = color
+			}
 		}
 	}
 
-	return true, steps
-}
+	var builder strings.Builder
+
+	builder.WriteString("digraph network {\n")
+
+	for _, node := range nodes {
+
+		switch rune(node[2]) {
+		case 'A':
+			builder.WriteString(fmt.Sprintf("  \"%s\" [style=filled, fillcolor=palegreen];\n", node))
+		case 'Z':
+			builder.WriteString(fmt.Sprintf("  \"%s\" [style=filled, fillcolor=salmon];\n", node))
+		default:
+			builder.WriteString(fmt.Sprintf("  \"%s\";\n", node))
+		}
+	}
+
+	for _, node := range nodes {
+
+		tuple := game.tuples[node]
 
-func getIndexOfSlowestState(states []State) int {
+		for _, edge := range []struct {
+			direction rune
+			target    string
+		}{{'L', tuple.left}, {'R', tuple.right}} {
 
-	slowestIndex := 0
-	slowestSteps := states[0].steps
+			attributes := fmt.Sprintf("label=\"%c\"", edge.direction)
 
-	for index, state := range states {
+			if color, marked := cycleEdgeColors[fmt.Sprintf("%s %c %s", node, edge.direction, edge.target)]; marked {
 
-		if state.steps < slowestSteps {
+				attributes += fmt.Sprintf(", color=%s, penwidth=2", color)
+			}
 
-			slowestIndex = index
+			builder.WriteString(fmt.Sprintf("  \"%s\" -> \"%s\" [%s];\n", node, edge.target, attributes))
 		}
 	}
 
-	return slowestIndex
+	builder.WriteString("}\n")
+
+	_, err := io.WriteString(writer, builder.String())
+
+	return err
 }
 
 func Part1(input string) string {
@@ -201,50 +414,29 @@ func Part2(input string) string {
 	game := parseGame(content)
 
 	// Get starting positions
-	var startPositions []string
-	var states []State
+	var ghosts []Ghost
 
-	for key, _ := range game.tuples {
+	for key := range game.tuples {
 
-		if rune(key[2]) == 'A' && !slices.Contains(startPositions, key) {
+		if rune(key[2]) == 'A' {
 
-			states = append(states, State{position: key, commandIndex: 0})
-			startPositions = append(startPositions, key)
+			ghosts = append(ghosts, analyseGhost(key, game))
 		}
 	}
 
-	// get offsets and loop sizes
-	var newStates []State
-
-	for _, state := range states {
+	steps, err := getAlignedSteps(ghosts)
 
-		state.offset, state.loopSequence = getOffsetAndLoop(state, game)
-		state.loopPosition = 0
-		state.steps = state.offset
+	if errors.Is(err, errNeverAligns) {
 
-		newStates = append(newStates, state)
+		return err.Error()
 	}
 
-	states = newStates
-
-	// until all states are aligned, increase the one with the fewest steps by one loop
-	for {
-
-		aligned, steps := areStatesAlignedAndSteps(states)
-
-		if aligned {
-			return strconv.FormatInt(steps, 10)
-		}
-
-		slowestIndex := getIndexOfSlowestState(states)
-
-		states[slowestIndex].loopPosition += 1
-		if states[slowestIndex].loopPosition >= len(states[slowestIndex].loopSequence) {
-			states[slowestIndex].loopPosition = 0
-		}
+	if err != nil {
 
-		states[slowestIndex].steps += states[slowestIndex].loopSequence[states[slowestIndex].loopPosition]
+		panic(err)
 	}
+
+	return steps.String()
 }
 
 func GetContent(filepath string) string {
@@ -2,14 +2,95 @@ package main
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 )
 
 var P1_IN_TEST = []string{fmt.Sprintf("test/%s/in01.txt", DAY), fmt.Sprintf("test/%s/in02.txt", DAY)}
 var P1_OUT_TEST = []string{"2", "6"}
 
-var P2_IN_TEST = []string{fmt.Sprintf("test/%s/in03.txt", DAY)}
-var P2_OUT_TEST = []string{"6"}
+var P2_IN_TEST = []string{fmt.Sprintf("test/%s/in03.txt", DAY), fmt.Sprintf("test/%s/in04.txt", DAY)}
+var P2_OUT_TEST = []string{"6", "ghosts never align"}
+
+func TestSolveCongruences(t *testing.T) {
+
+	testIn := [][3][]int64{
+		{{2, 3}, {4, 6}, {0}},
+		{{1, 3}, {4, 6}, {0}},
+		{{0, 0, 0}, {6, 10, 15}, {1}},
+		{{5, 1}, {8, 12}, {0}},
+	}
+	testOut := []string{"never", "9", "30", "13"}
+
+	for index, test := range testIn {
+
+		received := "never"
+
+		if steps, solvable := solveCongruences(test[0], test[1], test[2][0]); solvable {
+
+			received = steps.String()
+		}
+
+		assert("solveCongruences", fmt.Sprintf("%v", test), testOut[index], received, t)
+	}
+}
+
+func TestGetAlignedSteps(t *testing.T) {
+
+	testIn := "L\n\n11A = (11Z, XXX)\n11Z = (11B, XXX)\n11B = (11Z, XXX)\n22A = (22B, XXX)\n22B = (22Z, XXX)\n22Z = (22C, XXX)\n22C = (22Z, XXX)\nXXX = (XXX, XXX)"
+
+	game := parseGame(testIn)
+
+	_, err := getAlignedSteps([]Ghost{analyseGhost("11A", game), analyseGhost("22A", game)})
+
+	assert("getAlignedSteps", testIn, errNeverAligns.Error(), fmt.Sprintf("%v", err), t)
+
+	// a ghost whose only finish position lies before its cycle
+	testIn = "L\n\n11A = (11Z, XXX)\n11Z = (XXX, XXX)\n22A = (22Z, XXX)\n22Z = (22Z, XXX)\nXXX = (XXX, XXX)"
+
+	game = parseGame(testIn)
+
+	steps, _ := getAlignedSteps([]Ghost{analyseGhost("11A", game), analyseGhost("22A", game)})
+
+	assert("getAlignedSteps", testIn, "1", steps.String(), t)
+}
+
+func TestTracePath(t *testing.T) {
+
+	testIn := P1_IN_TEST[1]
+	testOut := "AAA BBB AAA BBB AAA BBB ZZZ"
+
+	received := tracePath("AAA", parseGame(GetContent(testIn)), 6)
+
+	assert("tracePath", testIn, testOut, strings.Join(received, " "), t)
+}
+
+func TestWriteDot(t *testing.T) {
+
+	testIn := P2_IN_TEST[0]
+	testOut := []string{
+		"\"11A\" [style=filled, fillcolor=palegreen];",
+		"\"22Z\" [style=filled, fillcolor=salmon];",
+		"\"11B\" -> \"11Z\" [label=\"R\", color=blue, penwidth=2];",
+		"\"22Z\" -> \"22B\" [label=\"L\", color=darkorange, penwidth=2];",
+		"\"11A\" -> \"11B\" [label=\"L\"];",
+	}
+
+	var builder strings.Builder
+
+	if err := writeDot(&builder, parseGame(GetContent(testIn))); err != nil {
+
+		t.Fatal(err)
+	}
+
+	for _, expected := range testOut {
+
+		if !strings.Contains(builder.String(), expected) {
+
+			t.Errorf("writeDot(%s) expected '%s' in '%s'", testIn, expected, builder.String())
+		}
+	}
+}
 
 func TestPart1(t *testing.T) {
 
@@ -1,7 +1,11 @@
 package main
 
 import (
+	"days/24/polygon"
+	"flag"
 	"fmt"
+	"html"
+	"io"
 	"log"
 	"os"
 	"strconv"
@@ -17,7 +21,41 @@ type Game struct {
 	limitX      int
 	limitY      int
 	distances   [][]int64
-	tilesInLoop [][]int
+	tilesInLoop [][]TileState
+}
+
+type TileState int
+
+const (
+	Unmarked TileState = iota
+	Enclosed
+	OnLoop
+)
+
+type Layer int
+
+const (
+	FieldsLayer Layer = iota + 1
+	TilesLayer
+	DistancesLayer
+)
+
+type Format int
+
+const (
+	PlainText Format = iota + 1
+	HTML
+)
+
+type RenderOptions struct {
+	hidden map[Layer]bool
+	format Format
+}
+
+var layerNames = map[Layer]string{
+	FieldsLayer:    "fields",
+	TilesLayer:     "tiles",
+	DistancesLayer: "distances",
 }
 
 type Direction int
@@ -29,25 +67,32 @@ const (
 	West
 )
 
-type Explorer struct {
+type Point struct {
 	positionX int
 	positionY int
-	direction Direction
 }
 
-func printDistances(game Game) {
-
-	var output = ""
-
-	for y := 0; y < len(game.distances); y++ {
+type Loop struct {
+	tiles     []Point
+	startPipe string
+}
 
-		for x := 0; x < len(game.distances[y]); x++ {
+var pipeConnections = map[string][]Direction{
+	"|": {North, South},
+	"-": {East, West},
+	"L": {North, East},
+	"J": {North, West},
+	"7": {South, West},
+	"F": {South, East},
+}
 
-			output += strings.Replace(fmt.Sprintf("%d", game.distances[y][x]), "0", ".", 1)
-		}
-		output += "\n"
-	}
-	fmt.Println(output)
+var boxDrawings = map[string]string{
+	"|": "│",
+	"-": "─",
+	"L": "└",
+	"J": "┘",
+	"7": "┐",
+	"F": "┌",
 }
 
 func parseGame(input string) Game {
@@ -71,210 +116,357 @@ func parseGame(input string) Game {
 
 		game.fields = append(game.fields, characters)
 		game.distances = append(game.distances, make([]int64, len(characters)))
-		game.tilesInLoop = append(game.tilesInLoop, make([]int, len(characters)))
+		game.tilesInLoop = append(game.tilesInLoop, make([]TileState, len(characters)))
 	}
 
 	game.limitY = len(game.fields)
 	game.limitX = len(game.fields[0])
 
-	game.tilesInLoop[game.startY][game.startX] = 2
+	game.tilesInLoop[game.startY][game.startX] = OnLoop
 
 	return game
 }
 
-func calculateDistances(game *Game) {
+func getOpposite(direction Direction) Direction {
 
-	foundLoop := calculateDistancesWithExplorer(game, Explorer{positionX: game.startX, positionY: game.startY, direction: North}, 0, false)
-	foundLoop = calculateDistancesWithExplorer(game, Explorer{positionX: game.startX, positionY: game.startY, direction: East}, 0, foundLoop)
-	foundLoop = calculateDistancesWithExplorer(game, Explorer{positionX: game.startX, positionY: game.startY, direction: South}, 0, foundLoop)
-	foundLoop = calculateDistancesWithExplorer(game, Explorer{positionX: game.startX, positionY: game.startY, direction: West}, 0, foundLoop)
+	switch direction {
+	case North:
+		return South
+	case East:
+		return West
+	case South:
+		return North
+	}
 
+	return East
 }
 
-func calculateDistancesWithExplorer(game *Game, explorer Explorer, distance int64, loopAlreadyFound bool) bool {
+func move(point Point, direction Direction) Point {
+
+	switch direction {
+	case North:
+		point.positionY--
+	case East:
+		point.positionX++
+	case South:
+		point.positionY++
+	case West:
+		point.positionX--
+	}
 
-	moveExplorer(&explorer)
+	return point
+}
 
-	newDistance := distance + 1
+func connects(pipe string, direction Direction) bool {
 
-	if explorer.positionX < 0 || explorer.positionX >= game.limitX || explorer.positionY < 0 || explorer.positionY >= game.limitY {
+	for _, connection := range pipeConnections[pipe] {
 
-		return false
-	}
+		if connection == direction {
 
-	switch game.fields[explorer.positionY][explorer.positionX] {
-	case ".":
-		return false
-	case "S":
-		return true
-	case "|":
-		if !(explorer.direction == South || explorer.direction == North) {
-			return false
-		}
-	case "-":
-		if !(explorer.direction == West || explorer.direction == East) {
-			return false
-		}
-	case "L":
-		if !(explorer.direction == South || explorer.direction == West) {
-			return false
-		}
-		if explorer.direction == South {
-			explorer.direction = East
-		} else if explorer.direction == West {
-			explorer.direction = North
+			return true
 		}
-	case "J":
-		if !(explorer.direction == South || explorer.direction == East) {
-			return false
+	}
+
+	return false
+}
+
+// the start tile holds a pipe connecting two neighbours that point back to it, stray pipes may point at it as well
+func getStartCandidates(game Game) []string {
+
+	var candidates []string
+
+	start := Point{positionX: game.startX, positionY: game.startY}
+
+	for _, pipe := range []string{"|", "-", "L", "J", "7", "F"} {
+
+		isCandidate := true
+
+		for _, direction := range pipeConnections[pipe] {
+
+			neighbour := move(start, direction)
+
+			if !isInBounds(game, neighbour.positionX, neighbour.positionY) || !connects(game.fields[neighbour.positionY][neighbour.positionX], getOpposite(direction)) {
+
+				isCandidate = false
+			}
 		}
-		if explorer.direction == South {
-			explorer.direction = West
-		} else if explorer.direction == East {
-			explorer.direction = North
+
+		if isCandidate {
+
+			candidates = append(candidates, pipe)
 		}
-	case "7":
-		if !(explorer.direction == North || explorer.direction == East) {
-			return false
+	}
+
+	return candidates
+}
+
+func getPipe(game Game, loop Loop, point Point) string {
+
+	if point.positionX == game.startX && point.positionY == game.startY {
+
+		return loop.startPipe
+	}
+
+	return game.fields[point.positionY][point.positionX]
+}
+
+// tries every start pipe and keeps the first whose loop closes
+func traceLoop(game Game) (Loop, error) {
+
+	candidates := getStartCandidates(game)
+
+	if len(candidates) == 0 {
+
+		return Loop{}, fmt.Errorf("start tile at (%d, %d) has no two connecting neighbours", game.startX, game.startY)
+	}
+
+	var err error
+
+	for _, startPipe := range candidates {
+
+		var loop Loop
+
+		if loop, err = followLoop(game, startPipe); err == nil {
+
+			return loop, nil
 		}
-		if explorer.direction == North {
-			explorer.direction = West
-		} else if explorer.direction == East {
-			explorer.direction = South
+	}
+
+	return Loop{}, err
+}
+
+// follows the pipes from the start until it is reached again through the other end of the start pipe
+func followLoop(game Game, startPipe string) (Loop, error) {
+
+	loop := Loop{startPipe: startPipe}
+
+	start := Point{positionX: game.startX, positionY: game.startY}
+	current := start
+	direction := pipeConnections[startPipe][0]
+
+	for {
+
+		loop.tiles = append(loop.tiles, current)
+		current = move(current, direction)
+
+		if current == start {
+
+			if !connects(startPipe, getOpposite(direction)) {
+
+				return loop, fmt.Errorf("loop returns to the start through a side '%s' does not connect", startPipe)
+			}
+
+			return loop, nil
 		}
-	case "F":
-		if !(explorer.direction == North || explorer.direction == West) {
-			return false
+
+		if !isInBounds(game, current.positionX, current.positionY) || !connects(game.fields[current.positionY][current.positionX], getOpposite(direction)) {
+
+			return loop, fmt.Errorf("pipe at (%d, %d) does not continue the loop", current.positionX, current.positionY)
 		}
-		if explorer.direction == North {
-			explorer.direction = East
-		} else if explorer.direction == West {
-			explorer.direction = South
+
+		for _, connection := range pipeConnections[game.fields[current.positionY][current.positionX]] {
+
+			if connection != getOpposite(direction) {
+
+				direction = connection
+				break
+			}
 		}
 	}
+}
+
+func calculateDistances(game *Game, loop Loop) {
 
-	if game.distances[explorer.positionY][explorer.positionX] == 0 || game.distances[explorer.positionY][explorer.positionX] > newDistance {
-		game.distances[explorer.positionY][explorer.positionX] = newDistance
+	for index, tile := range loop.tiles {
+
+		game.distances[tile.positionY][tile.positionX] = int64(min(index, len(loop.tiles)-index))
+		game.tilesInLoop[tile.positionY][tile.positionX] = OnLoop
 	}
-	loopFound := calculateDistancesWithExplorer(game, explorer, newDistance, loopAlreadyFound)
+}
+
+// the loop tiles are lattice points of a polygon, so Pick's theorem counts the enclosed tiles
+func countEnclosedTilesShoelace(loop Loop) int {
 
-	if !(loopFound) {
+	var vertices []polygon.Point
 
-		game.distances[explorer.positionY][explorer.positionX] = 0
-	} else {
+	for _, tile := range loop.tiles {
 
-		// 2 means there is a pipe of the loop
-		game.tilesInLoop[explorer.positionY][explorer.positionX] = 2
+		vertices = append(vertices, polygon.Point{X: tile.positionX, Y: tile.positionY})
 	}
 
-	if loopAlreadyFound {
+	vertices = append(vertices, vertices[0])
 
-		// mark tiles
-		switch game.fields[explorer.positionY][explorer.positionX] {
-		case "|":
-			if explorer.direction == North {
-				floodTiles(game, explorer.positionX+1, explorer.positionY)
-			} else if explorer.direction == South {
-				floodTiles(game, explorer.positionX-1, explorer.positionY)
-			}
-		case "-":
-			if explorer.direction == East {
-				floodTiles(game, explorer.positionX, explorer.positionY+1)
-			} else if explorer.direction == West {
-				floodTiles(game, explorer.positionX, explorer.positionY-1)
-			}
-		case "L":
-			if explorer.direction == East {
-				floodTiles(game, explorer.positionX-1, explorer.positionY)
-				floodTiles(game, explorer.positionX, explorer.positionY+1)
-				floodTiles(game, explorer.positionX-1, explorer.positionY+1)
-			}
-		case "J":
-			if explorer.direction == North {
-				floodTiles(game, explorer.positionX+1, explorer.positionY)
-				floodTiles(game, explorer.positionX, explorer.positionY+1)
-				floodTiles(game, explorer.positionX+1, explorer.positionY+1)
-			}
-		case "7":
-			if explorer.direction == West {
-				floodTiles(game, explorer.positionX, explorer.positionY-1)
-				floodTiles(game, explorer.positionX+1, explorer.positionY)
-				floodTiles(game, explorer.positionX+1, explorer.positionY-1)
-			}
-		case "F":
-			if explorer.direction == South {
-				floodTiles(game, explorer.positionX-1, explorer.positionY)
-				floodTiles(game, explorer.positionX, explorer.positionY-1)
-				floodTiles(game, explorer.positionX-1, explorer.positionY-1)
+	return polygon.Interior(vertices)
+}
+
+// scans every row and toggles inside whenever a loop pipe connects to the north, marks the enclosed tiles
+func countEnclosedTilesRayCasting(game *Game, loop Loop) int {
+
+	count := 0
+
+	for y := 0; y < game.limitY; y++ {
+
+		inside := false
+
+		for x := 0; x < game.limitX; x++ {
+
+			if game.tilesInLoop[y][x] == OnLoop {
+
+				if connects(getPipe(*game, loop, Point{positionX: x, positionY: y}), North) {
+
+					inside = !inside
+				}
+			} else if inside {
+
+				game.tilesInLoop[y][x] = Enclosed
+				count++
 			}
 		}
-
 	}
 
-	return loopFound
+	return count
 }
 
-func floodTiles(game *Game, positionX int, positionY int) {
+// draws the loop with box drawing characters, enclosed tiles as I and all others as O
+func renderMaze(game Game, loop Loop) string {
 
-	if !isInBounds(*game, positionX, positionY) {
-		return
-	}
+	var builder strings.Builder
 
-	if game.tilesInLoop[positionY][positionX] != 0 {
-		return
+	for y := 0; y < game.limitY; y++ {
+
+		for x := 0; x < game.limitX; x++ {
+
+			switch game.tilesInLoop[y][x] {
+			case OnLoop:
+				builder.WriteString(boxDrawings[getPipe(game, loop, Point{positionX: x, positionY: y})])
+			case Enclosed:
+				builder.WriteString("I")
+			default:
+				builder.WriteString("O")
+			}
+		}
+
+		builder.WriteString("\n")
 	}
 
-	// marks a tile
-	game.tilesInLoop[positionY][positionX] = 1
+	return builder.String()
+}
 
-	floodTiles(game, positionX-1, positionY)
-	floodTiles(game, positionX+1, positionY)
-	floodTiles(game, positionX, positionY-1)
-	floodTiles(game, positionX, positionY+1)
+func isInBounds(game Game, positionX int, positionY int) bool {
+
+	return 0 <= positionX && positionX < game.limitX && 0 <= positionY && positionY < game.limitY
 }
 
-func countMarkedTiles(game Game) (int64, int64) {
+func parseHiddenLayers(input string) (map[Layer]bool, error) {
 
-	sumMarked := int64(0)
-	sumUnmarked := int64(0)
+	hidden := make(map[Layer]bool)
 
-	for y := 0; y < len(game.tilesInLoop); y++ {
+	for _, name := range strings.Split(input, ",") {
 
-		for x := 0; x < len(game.tilesInLoop[y]); x++ {
+		name = strings.TrimSpace(name)
 
-			if game.tilesInLoop[y][x] == 1 {
+		if name == "" {
 
-				sumMarked++
-			} else if game.tilesInLoop[y][x] == 0 {
+			continue
+		}
+
+		known := false
+
+		for layer, layerName := range layerNames {
 
-				sumUnmarked++
+			if layerName == name {
+
+				hidden[layer] = true
+				known = true
 			}
 		}
+
+		if !known {
+
+			return nil, fmt.Errorf("unknown layer '%s'", name)
+		}
 	}
 
-	return sumMarked, sumUnmarked
+	return hidden, nil
 }
 
-func isInBounds(game Game, positionX int, positionY int) bool {
+// returns the text of the top most visible layer at the tile and that layer
+func getCell(game Game, x int, y int, options RenderOptions) (string, Layer) {
 
-	return 0 <= positionX && positionX < game.limitX && 0 <= positionY && positionY < game.limitY
+	if !options.hidden[DistancesLayer] && game.tilesInLoop[y][x] == OnLoop {
+
+		return strconv.FormatInt(game.distances[y][x], 10), DistancesLayer
+	}
+
+	if !options.hidden[TilesLayer] {
+
+		switch game.tilesInLoop[y][x] {
+		case OnLoop:
+			return "*", TilesLayer
+		case Enclosed:
+			return "I", TilesLayer
+		}
+	}
+
+	if !options.hidden[FieldsLayer] {
+
+		return game.fields[y][x], FieldsLayer
+	}
+
+	return " ", 0
 }
 
-func moveExplorer(explorer *Explorer) {
+// overlays distances over loop and enclosed tiles over the original fields, cells are padded to the widest distance
+func writeDebug(writer io.Writer, game Game, options RenderOptions) error {
+
+	width := 1
+
+	if !options.hidden[DistancesLayer] {
+
+		width = len(strconv.FormatInt(getMax(game.distances), 10))
+	}
+
+	var builder strings.Builder
+
+	if options.format == HTML {
+
+		builder.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>Day 10</title>\n<style>\n")
+		builder.WriteString("pre { font-family: monospace; line-height: 1; }\n.fields { color: #999; }\n.tiles { color: #0a0; font-weight: bold; }\n.distances { color: #c60; }\n")
+		builder.WriteString("</style>\n</head>\n<body>\n<pre>\n")
+	}
+
+	for y := 0; y < game.limitY; y++ {
+
+		for x := 0; x < game.limitX; x++ {
 
-	if explorer.direction == North {
+			cell, layer := getCell(game, x, y, options)
+			cell = strings.Repeat(" ", width-len(cell)) + cell
 
-		explorer.positionY--
-	} else if explorer.direction == East {
+			if options.format == HTML {
 
-		explorer.positionX++
-	} else if explorer.direction == South {
+				builder.WriteString(fmt.Sprintf("<span class=\"%s\" title=\"(%d, %d) %s distance %d\">%s</span>", layerNames[layer], x, y, html.EscapeString(game.fields[y][x]), game.distances[y][x], html.EscapeString(cell)))
+			} else {
 
-		explorer.positionY++
-	} else if explorer.direction == West {
+				builder.WriteString(cell)
+			}
+
+			if width > 1 && x < game.limitX-1 {
+
+				builder.WriteString(" ")
+			}
+		}
 
-		explorer.positionX--
+		builder.WriteString("\n")
 	}
+
+	if options.format == HTML {
+
+		builder.WriteString("</pre>\n</body>\n</html>\n")
+	}
+
+	_, err := io.WriteString(writer, builder.String())
+
+	return err
 }
 
 func getMax(array [][]int64) int64 {
@@ -290,13 +482,27 @@ func getMax(array [][]int64) int64 {
 	return maxValue
 }
 
+func analyseMaze(input string) (Game, Loop) {
+
+	game := parseGame(input)
+
+	loop, err := traceLoop(game)
+
+	if err != nil {
+
+		panic(err)
+	}
+
+	calculateDistances(&game, loop)
+
+	return game, loop
+}
+
 func Part1(input string) string {
 
 	content := GetContent(input)
 
-	game := parseGame(content)
-
-	calculateDistances(&game)
+	game, _ := analyseMaze(content)
 
 	return strconv.FormatInt(getMax(game.distances), 10)
 }
@@ -305,14 +511,9 @@ func Part2(input string) string {
 
 	content := GetContent(input)
 
-	game := parseGame(content)
-
-	calculateDistances(&game)
+	_, loop := analyseMaze(content)
 
-	optionA, optionB := countMarkedTiles(game)
-
-	// just guess that it is the minimum lol
-	return strconv.FormatInt(min(optionB, optionA), 10)
+	return strconv.Itoa(countEnclosedTilesShoelace(loop))
 }
 
 func GetContent(filepath string) string {
@@ -340,6 +541,37 @@ func stringToNumber(s string) int64 {
 
 func main() {
 
+	debug := flag.Bool("debug", false, "render the maze layers instead of solving")
+	hide := flag.String("hide", "", "comma separated layers to hide: fields, tiles, distances")
+	asHTML := flag.Bool("html", false, "render the layers as an HTML page")
+	flag.Parse()
+
+	if *debug {
+
+		hidden, err := parseHiddenLayers(*hide)
+
+		if err != nil {
+
+			log.Fatal(err)
+		}
+
+		options := RenderOptions{hidden: hidden, format: PlainText}
+
+		if *asHTML {
+
+			options.format = HTML
+		}
+
+		game, loop := analyseMaze(GetContent(fmt.Sprintf("input/%s/in.txt", DAY)))
+		countEnclosedTilesRayCasting(&game, loop)
+
+		if err := writeDebug(os.Stdout, game, options); err != nil {
+
+			log.Fatal(err)
+		}
+		return
+	}
+
 	fmt.Println(fmt.Sprintf("Part 1: %s", Part1(fmt.Sprintf("input/%s/in.txt", DAY))))
 	fmt.Println(fmt.Sprintf("Part 2: %s", Part2(fmt.Sprintf("input/%s/in.txt", DAY))))
 }
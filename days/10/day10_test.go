@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -11,6 +12,127 @@ var P1_OUT_TEST = []string{"4", "8"}
 var P2_IN_TEST = []string{fmt.Sprintf("test/%s/in03.txt", DAY), fmt.Sprintf("test/%s/in04.txt", DAY), fmt.Sprintf("test/%s/in05.txt", DAY), fmt.Sprintf("test/%s/in06.txt", DAY)}
 var P2_OUT_TEST = []string{"4", "4", "8", "10"}
 
+func TestTraceLoop(t *testing.T) {
+
+	testIn := P1_IN_TEST[0]
+
+	loop, err := traceLoop(parseGame(GetContent(testIn)))
+
+	if err != nil {
+
+		t.Fatal(err)
+	}
+
+	assert("traceLoop", testIn, "F 8", fmt.Sprintf("%s %d", loop.startPipe, len(loop.tiles)), t)
+
+	// the "-" left of the start points at it as well, so "-" and "7" are tried before "F"
+	testIn = ".....\n-S-7.\n.|.|.\n.L-J.\n....."
+	loop, err = traceLoop(parseGame(testIn))
+
+	if err != nil {
+
+		t.Fatal(err)
+	}
+
+	assert("traceLoop", testIn, "F 8", fmt.Sprintf("%s %d", loop.startPipe, len(loop.tiles)), t)
+
+	if _, err := traceLoop(parseGame("...\n.S.\n...")); err == nil {
+
+		t.Errorf("traceLoop(.S.) expected an error")
+	}
+}
+
+func TestEnclosedTiles(t *testing.T) {
+
+	for index, element := range P2_IN_TEST {
+
+		game, loop := analyseMaze(GetContent(element))
+
+		assert("countEnclosedTilesShoelace", element, P2_OUT_TEST[index], fmt.Sprintf("%d", countEnclosedTilesShoelace(loop)), t)
+		assert("countEnclosedTilesRayCasting", element, P2_OUT_TEST[index], fmt.Sprintf("%d", countEnclosedTilesRayCasting(&game, loop)), t)
+	}
+
+	testIn := P2_IN_TEST[0]
+	testOut := []string{
+		"OOOOOOOOOOO",
+		"O┌───────┐O",
+		"O│┌─────┐│O",
+		"O││OOOOO││O",
+		"O││OOOOO││O",
+		"O│└─┐O┌─┘│O",
+		"O│II│O│II│O",
+		"O└──┘O└──┘O",
+		"OOOOOOOOOOO",
+	}
+
+	game, loop := analyseMaze(GetContent(testIn))
+	countEnclosedTilesRayCasting(&game, loop)
+
+	assert("renderMaze", testIn, strings.Join(testOut, "\n")+"\n", renderMaze(game, loop), t)
+}
+
+func TestWriteDebug(t *testing.T) {
+
+	testIn := P1_IN_TEST[1]
+	testOut := map[string][]string{
+		"": {
+			"7-45-",
+			".2367",
+			"01I78",
+			"14567",
+			"23.LJ",
+		},
+		"distances": {
+			"7-**-",
+			".***7",
+			"**I**",
+			"*****",
+			"**.LJ",
+		},
+		"distances,tiles": {
+			"7-F7-",
+			".FJ|7",
+			"SJLL7",
+			"|F--J",
+			"LJ.LJ",
+		},
+	}
+
+	game, loop := analyseMaze(GetContent(testIn))
+	countEnclosedTilesRayCasting(&game, loop)
+
+	for hide, expected := range testOut {
+
+		hidden, err := parseHiddenLayers(hide)
+
+		if err != nil {
+
+			t.Fatal(err)
+		}
+
+		var builder strings.Builder
+
+		if err := writeDebug(&builder, game, RenderOptions{hidden: hidden, format: PlainText}); err != nil {
+
+			t.Fatal(err)
+		}
+
+		assert("writeDebug", hide, strings.Join(expected, "\n")+"\n", builder.String(), t)
+	}
+
+	var builder strings.Builder
+
+	if err := writeDebug(&builder, game, RenderOptions{format: HTML}); err != nil || !strings.Contains(builder.String(), `<span class="distances" title="(0, 2) S distance 0">0</span>`) {
+
+		t.Errorf("writeDebug(%s) expected an HTML page but received '%s'", testIn, builder.String())
+	}
+
+	if _, err := parseHiddenLayers("tiles,pipes"); err == nil {
+
+		t.Errorf("parseHiddenLayers(tiles,pipes) expected an error")
+	}
+}
+
 func TestPart1(t *testing.T) {
 
 	for index, element := range P1_IN_TEST {
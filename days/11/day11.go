@@ -1,9 +1,11 @@
 package main
 
 import (
+	"cmp"
 	"fmt"
 	"log"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -15,14 +17,16 @@ type Galaxy struct {
 	y int
 }
 
-type EmptySpace struct {
-	size int64
+type Universe struct {
+	galaxies []Galaxy
+	// number of empty columns/rows with a smaller index than the position
+	emptyColumnsBefore []int64
+	emptyRowsBefore    []int64
 }
 
-type Universe struct {
-	galaxies         []Galaxy
-	horizontalSpaces map[int]EmptySpace
-	verticalSpaces   map[int]EmptySpace
+type Neighbour struct {
+	galaxy   int
+	distance int64
 }
 
 func parseUniverse(input string) Universe {
@@ -31,124 +35,177 @@ func parseUniverse(input string) Universe {
 
 	lines := strings.Split(strings.ReplaceAll(input, "\r", ""), "\n")
 
-	// contain rows/columns which are empty
-	var horizontalSpaces map[int]bool = make(map[int]bool)
-	var verticalSpaces map[int]bool = make(map[int]bool)
-
-	for i := 0; i < len(lines); i++ {
-		horizontalSpaces[i] = true
-	}
-	for i := 0; i < len(lines[0]); i++ {
-		verticalSpaces[i] = true
-	}
+	// contain rows/columns which hold galaxies
+	occupiedRows := make([]bool, len(lines))
+	occupiedColumns := make([]bool, len(lines[0]))
 
 	for y := 0; y < len(lines); y++ {
 		for x := 0; x < len(lines[y]); x++ {
-			if string(lines[y][x]) == "#" {
+			if lines[y][x] == '#' {
 				universe.galaxies = append(universe.galaxies, Galaxy{x: x, y: y})
-				delete(horizontalSpaces, y)
-				delete(verticalSpaces, x)
+				occupiedRows[y] = true
+				occupiedColumns[x] = true
 			}
 		}
 	}
 
-	universe.horizontalSpaces = make(map[int]EmptySpace)
-	universe.verticalSpaces = make(map[int]EmptySpace)
+	universe.emptyRowsBefore = getEmptyBefore(occupiedRows)
+	universe.emptyColumnsBefore = getEmptyBefore(occupiedColumns)
 
-	for key, _ := range horizontalSpaces {
-		universe.horizontalSpaces[key] = EmptySpace{size: 1}
-	}
-	for key, _ := range verticalSpaces {
-		universe.verticalSpaces[key] = EmptySpace{size: 1}
+	return universe
+}
+
+func getEmptyBefore(occupied []bool) []int64 {
+
+	emptyBefore := make([]int64, len(occupied)+1)
+
+	for index, isOccupied := range occupied {
+
+		emptyBefore[index+1] = emptyBefore[index]
+
+		if !isOccupied {
+
+			emptyBefore[index+1]++
+		}
 	}
 
-	return universe
+	return emptyBefore
 }
 
-func expandSpaces(universe *Universe, expansionFactor int64) {
+// every empty row and column counts expansionFactor times
+func getExpandedPosition(galaxy Galaxy, universe Universe, expansionFactor int64) (int64, int64) {
 
-	for key, _ := range universe.verticalSpaces {
-		universe.verticalSpaces[key] = EmptySpace{size: expansionFactor * universe.verticalSpaces[key].size}
+	x := int64(galaxy.x) + (expansionFactor-1)*universe.emptyColumnsBefore[galaxy.x]
+	y := int64(galaxy.y) + (expansionFactor-1)*universe.emptyRowsBefore[galaxy.y]
+
+	return x, y
+}
+
+func getDistance(first Galaxy, second Galaxy, universe Universe, expansionFactor int64) int64 {
+
+	firstX, firstY := getExpandedPosition(first, universe, expansionFactor)
+	secondX, secondY := getExpandedPosition(second, universe, expansionFactor)
+
+	return abs(firstX-secondX) + abs(firstY-secondY)
+}
+
+func abs(x int64) int64 {
+
+	if x < 0 {
+		return -x
 	}
-	for key, _ := range universe.horizontalSpaces {
-		universe.horizontalSpaces[key] = EmptySpace{size: expansionFactor * universe.horizontalSpaces[key].size}
+	return x
+}
+
+// the x and y parts of the manhattan distance are summed independently over sorted coordinates
+func getSumOfDistances(universe Universe, expansionFactor int64) int64 {
+
+	var xs []int64
+	var ys []int64
+
+	for _, galaxy := range universe.galaxies {
+
+		x, y := getExpandedPosition(galaxy, universe, expansionFactor)
+		xs = append(xs, x)
+		ys = append(ys, y)
 	}
+
+	return getSumOfDifferences(xs) + getSumOfDifferences(ys)
 }
 
-func getDistance(first Galaxy, second Galaxy, universe Universe) int64 {
+func getSumOfDifferences(values []int64) int64 {
+
+	slices.Sort(values)
 
-	minX := min(first.x, second.x)
-	maxX := max(first.x, second.x)
-	minY := min(first.y, second.y)
-	maxY := max(first.y, second.y)
+	sum := int64(0)
+	prefix := int64(0)
 
-	distance := int64(0)
+	for index, value := range values {
 
-	if minX != maxX {
-		distance += int64(maxX - minX)
-		for i := minX + 1; i < maxX; i++ {
-			emptySpace, ok := universe.verticalSpaces[i]
-			if ok {
-				distance += emptySpace.size - 1
-			}
-		}
+		sum += value*int64(index) - prefix
+		prefix += value
 	}
-	if minY != maxY {
-		distance += int64(maxY - minY)
-		for i := minY + 1; i < maxY; i++ {
-			emptySpace, ok := universe.horizontalSpaces[i]
-			if ok {
-				distance += emptySpace.size - 1
-			}
+
+	return sum
+}
+
+func getNearestGalaxies(universe Universe, galaxy int, k int, expansionFactor int64) []Neighbour {
+
+	var neighbours []Neighbour
+
+	for index, other := range universe.galaxies {
+
+		if index != galaxy {
+
+			neighbours = append(neighbours, Neighbour{galaxy: index, distance: getDistance(universe.galaxies[galaxy], other, universe, expansionFactor)})
 		}
 	}
 
-	return distance
+	slices.SortStableFunc(neighbours, func(a Neighbour, b Neighbour) int {
+
+		return cmp.Compare(a.distance, b.distance)
+	})
+
+	return neighbours[:min(k, len(neighbours))]
 }
 
-func Part1(input string) string {
+// the distance is linear in the expansion factor, so two matrices describe all factors
+func getDistanceMatrices(universe Universe, expansionFactors []int64) map[int64][][]int64 {
 
-	content := GetContent(input)
+	count := len(universe.galaxies)
+	base := make([][]int64, count)
+	emptyCrossed := make([][]int64, count)
 
-	universe := parseUniverse(content)
+	for first := range universe.galaxies {
 
-	expandSpaces(&universe, 2)
+		base[first] = make([]int64, count)
+		emptyCrossed[first] = make([]int64, count)
 
-	sum := int64(0)
+		for second := range universe.galaxies {
 
-	for _, first := range universe.galaxies {
-		for _, second := range universe.galaxies {
-			if !(first.x == second.x && first.y == second.y) {
-				sum += getDistance(first, second, universe)
-			}
+			base[first][second] = getDistance(universe.galaxies[first], universe.galaxies[second], universe, 1)
+			emptyCrossed[first][second] = getDistance(universe.galaxies[first], universe.galaxies[second], universe, 2) - base[first][second]
 		}
 	}
 
-	sum = sum / 2
+	matrices := make(map[int64][][]int64)
 
-	return strconv.FormatInt(sum, 10)
-}
+	for _, expansionFactor := range expansionFactors {
 
-func Part2(input string) string {
+		matrix := make([][]int64, count)
 
-	content := GetContent(input)
-	universe := parseUniverse(content)
+		for first := range matrix {
 
-	expandSpaces(&universe, 1000000)
+			matrix[first] = make([]int64, count)
 
-	sum := int64(0)
+			for second := range matrix[first] {
 
-	for _, first := range universe.galaxies {
-		for _, second := range universe.galaxies {
-			if !(first.x == second.x && first.y == second.y) {
-				sum += getDistance(first, second, universe)
+				matrix[first][second] = base[first][second] + (expansionFactor-1)*emptyCrossed[first][second]
 			}
 		}
+
+		matrices[expansionFactor] = matrix
 	}
 
-	sum = sum / 2
+	return matrices
+}
+
+func Part1(input string) string {
+
+	content := GetContent(input)
+
+	universe := parseUniverse(content)
+
+	return strconv.FormatInt(getSumOfDistances(universe, 2), 10)
+}
+
+func Part2(input string) string {
+
+	content := GetContent(input)
+
+	universe := parseUniverse(content)
 
-	return strconv.FormatInt(sum, 10)
+	return strconv.FormatInt(getSumOfDistances(universe, 1000000), 10)
 }
 
 func GetContent(filepath string) string {
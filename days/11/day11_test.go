@@ -11,6 +11,40 @@ var P1_OUT_TEST = []string{"374"}
 var P2_IN_TEST = []string{fmt.Sprintf("test/%s/in01.txt", DAY)}
 var P2_OUT_TEST = []string{"82000210"}
 
+func TestDistanceQueries(t *testing.T) {
+
+	testIn := P1_IN_TEST[0]
+
+	universe := parseUniverse(GetContent(testIn))
+
+	// galaxies 5 and 9, 1 and 7, 3 and 6 as well as 8 and 9 from the puzzle text
+	assert("getDistance", testIn, "9 15 17 5", fmt.Sprintf("%d %d %d %d",
+		getDistance(universe.galaxies[4], universe.galaxies[8], universe, 2),
+		getDistance(universe.galaxies[0], universe.galaxies[6], universe, 2),
+		getDistance(universe.galaxies[2], universe.galaxies[5], universe, 2),
+		getDistance(universe.galaxies[7], universe.galaxies[8], universe, 2)), t)
+
+	assert("getNearestGalaxies", testIn, "[{8 5} {4 6}]", fmt.Sprintf("%v", getNearestGalaxies(universe, 7, 2, 2)), t)
+
+	matrices := getDistanceMatrices(universe, []int64{2, 10, 100})
+
+	for expansionFactor, expected := range map[int64]int64{2: 374, 10: 1030, 100: 8410} {
+
+		sum := int64(0)
+
+		for first := range matrices[expansionFactor] {
+
+			for second := first + 1; second < len(matrices[expansionFactor]); second++ {
+
+				sum += matrices[expansionFactor][first][second]
+			}
+		}
+
+		assert("getDistanceMatrices", fmt.Sprintf("%d", expansionFactor), fmt.Sprintf("%d", expected), fmt.Sprintf("%d", sum), t)
+		assert("getSumOfDistances", fmt.Sprintf("%d", expansionFactor), fmt.Sprintf("%d", expected), fmt.Sprintf("%d", getSumOfDistances(universe, expansionFactor)), t)
+	}
+}
+
 func TestPart1(t *testing.T) {
 
 	for index, element := range P1_IN_TEST {
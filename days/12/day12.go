@@ -1,8 +1,12 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
+	"math"
+	"math/big"
+	"math/rand"
 	"os"
 	"regexp"
 	"strconv"
@@ -21,14 +25,20 @@ func (game Game) String() string {
 	return fmt.Sprintf("(%s, %#v)", game.path, game.groups)
 }
 
-func serialize(game Game) string {
-
-	// thanks to go, we need to stringify our game manually
-	return fmt.Sprintf("%s", game)
+type Solver struct {
+	// the path always ends with an artificial "." (we can always check the character after a sequence of "#")
+	path   string
+	groups []int
+	// runs[i] is the number of consecutive "#" or "?" starting at position i
+	runs     []int
+	cache    []int64
+	overflow bool
 }
 
-type Cache struct {
-	mapping map[string]int64
+type Arrangements struct {
+	solver Solver
+	// counts[position][group] is the number of arrangements of path[position:] using groups[group:]
+	counts [][]*big.Int
 }
 
 func parseGame(input string) Game {
@@ -39,8 +49,7 @@ func parseGame(input string) Game {
 
 	split := strings.Split(strings.ReplaceAll(input, "\r", ""), " ")
 
-	// append a "." for simpler recursion (we can always check the character after a sequence of "#")
-	game.path = split[0] + "."
+	game.path = split[0]
 
 	for _, numberString := range numberRe.FindAllString(split[1], -1) {
 
@@ -50,161 +59,341 @@ func parseGame(input string) Game {
 	return game
 }
 
-func calculatePossibilities(game Game, cache *Cache) int64 {
+func parseGames(content string) []Game {
+
+	var games []Game
+
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r", ""), "\n") {
 
-	// initialize cache
-	if cache == nil {
+		if len(line) > 0 {
 
-		newCache := Cache{mapping: make(map[string]int64)}
-		cache = &newCache
+			games = append(games, parseGame(line))
+		}
 	}
 
-	// check the cache for an answer
-	res, ok := cache.mapping[serialize(game)]
+	return games
+}
 
-	if ok {
+func unfoldGame(game Game, factor int, separator string) Game {
 
-		return res
+	if factor < 1 {
+
+		panic(fmt.Sprintf("unfold factor must be positive, got %d", factor))
 	}
 
-	if len(game.groups) == 0 && !strings.Contains(game.path, "#") {
+	paths := make([]string, factor)
+	groups := make([]int64, 0, factor*len(game.groups))
 
-		// we have no groups, but there are also none required
-		cache.mapping[serialize(game)] = 1
-		return 1
+	for i := 0; i < factor; i++ {
 
+		paths[i] = game.path
+		groups = append(groups, game.groups...)
 	}
 
-	if len(game.groups) == 0 && strings.Contains(game.path, "#") {
+	return Game{path: strings.Join(paths, separator), groups: groups}
+}
 
-		// we ran out of groups, but the path requires them
-		cache.mapping[serialize(game)] = 0
-		return 0
-	}
+func newSolver(game Game) Solver {
 
-	if len(game.path) == 0 {
+	solver := Solver{path: game.path + ".", groups: make([]int, len(game.groups))}
 
-		// the path ended, but we still have groups
-		cache.mapping[serialize(game)] = 0
-		return 0
+	for index, group := range game.groups {
+
+		solver.groups[index] = int(group)
 	}
 
-	// we have groups and path remaining
-	// process next character on path
-	if string(game.path[0]) == "." {
+	solver.runs = make([]int, len(solver.path)+1)
 
-		return treatDot(game, cache)
+	for position := len(solver.path) - 1; position >= 0; position-- {
+
+		if solver.path[position] != '.' {
+
+			solver.runs[position] = solver.runs[position+1] + 1
+		}
 	}
 
-	if string(game.path[0]) == "#" {
+	solver.cache = make([]int64, (len(solver.path)+1)*(len(solver.groups)+1))
 
-		return treatHashtag(game, cache)
+	for index := range solver.cache {
 
+		solver.cache[index] = -1
 	}
 
-	// game.path[0] must be "?", assume it is "." or "#" and add the result
-	possibilities := treatDot(game, cache) + treatHashtag(game, cache)
-	cache.mapping[serialize(game)] = possibilities
-	return possibilities
+	return solver
+}
 
+func getCacheKey(solver *Solver, position int, group int) int {
+
+	return position*(len(solver.groups)+1) + group
 }
 
-func treatDot(game Game, cache *Cache) int64 {
+func canSkip(solver *Solver, position int) bool {
 
-	// simply skip the dot in the path
-	possibilities := calculatePossibilities(Game{path: game.path[1:], groups: game.groups}, cache)
-	cache.mapping[serialize(game)] = possibilities
-	return possibilities
+	return solver.path[position] != '#'
+}
+
+func canPlaceGroup(solver *Solver, position int, group int) bool {
+
+	// the group has to fit into "#" and "?" and must not be followed by a "#"
+	size := solver.groups[group]
+	return solver.runs[position] >= size && solver.path[position+size] != '#'
 }
 
-func treatHashtag(game Game, cache *Cache) int64 {
+func calculatePossibilities(solver *Solver, position int, group int) int64 {
 
-	nextGroupSize := int(game.groups[0])
+	if position == len(solver.path) {
 
-	// can the group fit in the next segment of the path of "#"s?
-	if len(game.path) < nextGroupSize {
+		if group == len(solver.groups) {
+
+			return 1
+		}
 
-		// path is too short
-		cache.mapping[serialize(game)] = 0
 		return 0
 	}
 
-	pathSegment := game.path[0:nextGroupSize]
-	// treat "?" as "#" temporarily
-	pathSegment = strings.ReplaceAll(pathSegment, "?", "#")
-	groupString := strings.Repeat("#", nextGroupSize)
+	key := getCacheKey(solver, position, group)
 
-	if pathSegment != groupString {
+	if solver.cache[key] >= 0 {
 
-		// path does not contain sufficient "#" (or "?")
-		cache.mapping[serialize(game)] = 0
-		return 0
+		return solver.cache[key]
 	}
 
-	// check if group is too short
-	pathContinueIndex := nextGroupSize
+	possibilities := int64(0)
 
-	if string(game.path[pathContinueIndex]) == "#" {
+	if canSkip(solver, position) {
 
-		// path requires longer group
-		cache.mapping[serialize(game)] = 0
-		return 0
+		// treat the character as "."
+		possibilities = calculatePossibilities(solver, position+1, group)
 	}
 
-	// group fits perfectly, the path continues with "." or "?"
-	// remove the group, cut the path and continue
-	possibilities := calculatePossibilities(Game{path: game.path[pathContinueIndex+1:], groups: game.groups[1:]}, cache)
-	cache.mapping[serialize(game)] = possibilities
+	if group < len(solver.groups) && canPlaceGroup(solver, position, group) {
+
+		// treat the character as the start of the next group, the character after it as "."
+		placed := calculatePossibilities(solver, position+solver.groups[group]+1, group+1)
+
+		if possibilities > math.MaxInt64-placed {
+
+			solver.overflow = true
+			possibilities = math.MaxInt64
+		} else {
+
+			possibilities += placed
+		}
+	}
+
+	solver.cache[key] = possibilities
 	return possibilities
 }
 
-func blowUpGame(game *Game) {
+func countArrangements(game Game) (int64, bool) {
+
+	solver := newSolver(game)
+	count := calculatePossibilities(&solver, 0, 0)
+
+	return count, !solver.overflow
+}
 
-	// first remove the artificial dot
-	game.path = game.path[:len(game.path)-1]
+func newArrangements(game Game) Arrangements {
 
-	originalPath := game.path
-	originalGroups := make([]int64, len(game.groups))
-	copy(originalGroups, game.groups)
+	arrangements := Arrangements{solver: newSolver(game)}
+	solver := &arrangements.solver
 
-	for i := 0; i < 4; i++ {
+	arrangements.counts = make([][]*big.Int, len(solver.path)+1)
 
-		game.path = game.path + "?" + originalPath
-		game.groups = append(game.groups, originalGroups...)
+	for position := len(solver.path); position >= 0; position-- {
+
+		arrangements.counts[position] = make([]*big.Int, len(solver.groups)+1)
+
+		for group := len(solver.groups); group >= 0; group-- {
+
+			count := big.NewInt(0)
+
+			if position == len(solver.path) {
+
+				if group == len(solver.groups) {
+
+					count.SetInt64(1)
+				}
+			} else {
+
+				if canSkip(solver, position) {
+
+					count.Add(count, arrangements.counts[position+1][group])
+				}
+
+				if group < len(solver.groups) && canPlaceGroup(solver, position, group) {
+
+					count.Add(count, arrangements.counts[position+solver.groups[group]+1][group+1])
+				}
+			}
+
+			arrangements.counts[position][group] = count
+		}
 	}
 
-	// add the artificial dot again
-	game.path = game.path + "."
+	return arrangements
 }
 
-func Part1(input string) string {
+func getArrangementCount(game Game) *big.Int {
+
+	count, ok := countArrangements(game)
 
-	content := GetContent(input)
+	if ok {
 
-	sum := int64(0)
+		return big.NewInt(count)
+	}
 
-	for _, line := range strings.Split(strings.ReplaceAll(content, "\r", ""), "\n") {
+	// the count does not fit into an int64
+	return newArrangements(game).counts[0][0]
+}
+
+func getTotal(arrangements Arrangements) *big.Int {
+
+	return arrangements.counts[0][0]
+}
+
+func enumerateArrangements(arrangements Arrangements, limit int) []string {
+
+	var witnesses []string
+
+	buffer := make([]byte, len(arrangements.solver.path))
+	collectArrangements(arrangements, 0, 0, buffer, limit, &witnesses)
+
+	return witnesses
+}
+
+func collectArrangements(arrangements Arrangements, position int, group int, buffer []byte, limit int, witnesses *[]string) {
+
+	solver := &arrangements.solver
+
+	if len(*witnesses) >= limit || arrangements.counts[position][group].Sign() == 0 {
+
+		return
+	}
+
+	if position == len(solver.path) {
+
+		// drop the artificial dot
+		*witnesses = append(*witnesses, string(buffer[:len(buffer)-1]))
+		return
+	}
 
-		sum += calculatePossibilities(parseGame(line), nil)
+	if canSkip(solver, position) {
+
+		buffer[position] = '.'
+		collectArrangements(arrangements, position+1, group, buffer, limit, witnesses)
 	}
 
-	return strconv.FormatInt(sum, 10)
+	if group < len(solver.groups) && canPlaceGroup(solver, position, group) {
+
+		next := position + placeGroup(buffer, position, solver.groups[group])
+		collectArrangements(arrangements, next, group+1, buffer, limit, witnesses)
+	}
 }
 
-func Part2(input string) string {
+func placeGroup(buffer []byte, position int, size int) int {
 
-	content := GetContent(input)
+	for i := 0; i < size; i++ {
 
-	sum := int64(0)
+		buffer[position+i] = '#'
+	}
 
-	for _, line := range strings.Split(strings.ReplaceAll(content, "\r", ""), "\n") {
+	buffer[position+size] = '.'
+
+	return size + 1
+}
+
+func sampleArrangement(arrangements Arrangements, random *rand.Rand) (string, bool) {
+
+	solver := &arrangements.solver
+
+	if getTotal(arrangements).Sign() == 0 {
+
+		return "", false
+	}
+
+	buffer := make([]byte, len(solver.path))
+	position, group := 0, 0
+
+	for position < len(solver.path) {
+
+		skipped := big.NewInt(0)
+
+		if canSkip(solver, position) {
 
-		game := parseGame(line)
-		blowUpGame(&game)
-		sum += calculatePossibilities(game, nil)
+			skipped = arrangements.counts[position+1][group]
+		}
+
+		// pick the skip with probability skipped / total, so every arrangement is equally likely
+		pick := new(big.Int).Rand(random, arrangements.counts[position][group])
+
+		if pick.Cmp(skipped) < 0 {
+
+			buffer[position] = '.'
+			position++
+		} else {
+
+			position += placeGroup(buffer, position, solver.groups[group])
+			group++
+		}
 	}
 
-	return strconv.FormatInt(sum, 10)
+	return string(buffer[:len(buffer)-1]), true
+}
+
+func matchesGame(arrangement string, game Game) bool {
+
+	if len(arrangement) != len(game.path) {
+
+		return false
+	}
+
+	for index := range arrangement {
+
+		if game.path[index] != '?' && game.path[index] != arrangement[index] {
+
+			return false
+		}
+	}
+
+	fields := strings.FieldsFunc(arrangement, func(r rune) bool { return r == '.' })
+
+	if len(fields) != len(game.groups) {
+
+		return false
+	}
+
+	for index, field := range fields {
+
+		if int64(len(field)) != game.groups[index] || strings.Trim(field, "#") != "" {
+
+			return false
+		}
+	}
+
+	return true
+}
+
+func solve(input string, factor int, separator string) *big.Int {
+
+	sum := big.NewInt(0)
+
+	for _, game := range parseGames(GetContent(input)) {
+
+		sum.Add(sum, getArrangementCount(unfoldGame(game, factor, separator)))
+	}
+
+	return sum
+}
+
+func Part1(input string) string {
+
+	return solve(input, 1, "?").String()
+}
+
+func Part2(input string) string {
+
+	return solve(input, 5, "?").String()
 }
 
 func GetContent(filepath string) string {
@@ -232,6 +421,35 @@ func stringToNumber(s string) int64 {
 
 func main() {
 
+	factor := flag.Int("unfold", 0, "additionally solve the input unfolded this many times")
+	separator := flag.String("separator", "?", "separator placed between unfolded copies")
+	witnesses := flag.Int("witnesses", 0, "print up to this many arrangements per line of the unfolded input")
+	flag.Parse()
+
 	fmt.Println(fmt.Sprintf("Part 1: %s", Part1(fmt.Sprintf("input/%s/in.txt", DAY))))
 	fmt.Println(fmt.Sprintf("Part 2: %s", Part2(fmt.Sprintf("input/%s/in.txt", DAY))))
+
+	if *factor < 1 {
+
+		return
+	}
+
+	input := fmt.Sprintf("input/%s/in.txt", DAY)
+	fmt.Println(fmt.Sprintf("Unfolded %d times: %s", *factor, solve(input, *factor, *separator)))
+
+	if *witnesses < 1 {
+
+		return
+	}
+
+	for _, game := range parseGames(GetContent(input)) {
+
+		arrangements := newArrangements(unfoldGame(game, *factor, *separator))
+		fmt.Println(fmt.Sprintf("%s: %s arrangements", game, getTotal(arrangements)))
+
+		for _, witness := range enumerateArrangements(arrangements, *witnesses) {
+
+			fmt.Println("  " + witness)
+		}
+	}
 }
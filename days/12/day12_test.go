@@ -2,6 +2,9 @@ package main
 
 import (
 	"fmt"
+	"math/big"
+	"math/rand"
+	"strings"
 	"testing"
 )
 
@@ -11,6 +14,87 @@ var P1_OUT_TEST = []string{"21"}
 var P2_IN_TEST = []string{fmt.Sprintf("test/%s/in01.txt", DAY)}
 var P2_OUT_TEST = []string{"525152"}
 
+func TestUnfoldGame(t *testing.T) {
+
+	game := unfoldGame(parseGame(".# 1"), 3, "?")
+
+	assert("unfoldGame", ".# 1", "(.#?.#?.#, []int64{1, 1, 1})", game.String(), t)
+	assert("unfoldGame", ".??..??...?##. 1,1,3", "32", getArrangementCount(unfoldGame(parseGame(".??..??...?##. 1,1,3"), 2, "?")).String(), t)
+	assert("unfoldGame", ".??..??...?##. 1,1,3", "16", getArrangementCount(unfoldGame(parseGame(".??..??...?##. 1,1,3"), 2, ".")).String(), t)
+}
+
+func TestGetArrangementCount(t *testing.T) {
+
+	// n "?" with k groups of size 1 allow binomial(n - k + 1, k) arrangements
+	n, k := 200, 40
+	game := Game{path: strings.Repeat("?", n)}
+
+	for i := 0; i < k; i++ {
+
+		game.groups = append(game.groups, 1)
+	}
+
+	if _, ok := countArrangements(game); ok {
+
+		t.Errorf("countArrangements(%d, %d) expected to overflow", n, k)
+	}
+
+	expected := new(big.Int).Binomial(int64(n-k+1), int64(k))
+	assert("getArrangementCount", game.path, expected.String(), getArrangementCount(game).String(), t)
+}
+
+func TestEnumerateArrangements(t *testing.T) {
+
+	testIn := []string{"???.### 1,1,3", "?###???????? 3,2,1", "#.# 2"}
+	testOut := []int{1, 10, 0}
+
+	for index, input := range testIn {
+
+		game := parseGame(input)
+		arrangements := newArrangements(game)
+		witnesses := enumerateArrangements(arrangements, 100)
+		seen := make(map[string]bool)
+
+		for _, witness := range witnesses {
+
+			if !matchesGame(witness, game) || seen[witness] {
+
+				t.Errorf("enumerateArrangements(%s) returned invalid or duplicate '%s'", input, witness)
+			}
+
+			seen[witness] = true
+		}
+
+		assert("enumerateArrangements", input, fmt.Sprint(testOut[index]), fmt.Sprint(len(witnesses)), t)
+		assert("enumerateArrangements", input, getTotal(arrangements).String(), fmt.Sprint(len(witnesses)), t)
+	}
+
+	assert("enumerateArrangements", "???.### 1,1,3", "#.#.###", enumerateArrangements(newArrangements(parseGame("???.### 1,1,3")), 1)[0], t)
+	assert("enumerateArrangements", "limit", "3", fmt.Sprint(len(enumerateArrangements(newArrangements(parseGame("?###???????? 3,2,1")), 3))), t)
+}
+
+func TestSampleArrangement(t *testing.T) {
+
+	random := rand.New(rand.NewSource(12))
+	game := unfoldGame(parseGame("?###???????? 3,2,1"), 5, "?")
+	arrangements := newArrangements(game)
+
+	for i := 0; i < 100; i++ {
+
+		witness, ok := sampleArrangement(arrangements, random)
+
+		if !ok || !matchesGame(witness, game) {
+
+			t.Errorf("sampleArrangement(%s) returned invalid '%s'", game, witness)
+		}
+	}
+
+	if _, ok := sampleArrangement(newArrangements(parseGame("#.# 2")), random); ok {
+
+		t.Errorf("sampleArrangement(#.# 2) expected no arrangement")
+	}
+}
+
 func TestPart1(t *testing.T) {
 
 	for index, element := range P1_IN_TEST {
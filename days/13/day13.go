@@ -1,6 +1,7 @@
 package main
 
 import (
+	"days/24/bitgrid"
 	"fmt"
 	"log"
 	"os"
@@ -11,41 +12,68 @@ import (
 const DAY = "13"
 
 type Data struct {
-	rows    []string
-	columns []string
+	lines   []string
+	rows    []bitgrid.Bits
+	columns []bitgrid.Bits
 }
 
-func getNumberOfLinesVertical(data Data, smudges int) int64 {
+type Axis int
 
-	sum := int64(0)
+const (
+	Vertical Axis = iota + 1
+	Horizontal
+)
 
-	for reflectionLineIndex := 0; reflectionLineIndex < len(data.columns)-1; reflectionLineIndex++ {
+type Smudge struct {
+	x       int
+	y       int
+	mirrorX int
+	mirrorY int
+}
 
-		if checkHorizontalReflection(data, reflectionLineIndex, true, smudges) {
+type Reflection struct {
+	axis      Axis
+	index     int
+	smudges   []Smudge
+	corrected []string
+}
 
-			sum += int64(reflectionLineIndex + 1)
-		}
-	}
+type PatternResult struct {
+	pattern     int
+	reflections []Reflection
+}
 
-	return sum
+func (b Reflection) String() string {
+
+	return fmt.Sprintf("Reflection(axis=%d, index=%d, smudges=%v, corrected=%#v)", b.axis, b.index, b.smudges, b.corrected)
 }
 
-func getNumberOfLinesHorizontal(data Data, smudges int) int64 {
+// returns every reflection axis with exactly the given number of smudges, index counts the lines before the axis
+func findReflections(data Data, smudges int) []Reflection {
 
-	sum := int64(0)
+	var reflections []Reflection
+
+	for reflectionLineIndex := 0; reflectionLineIndex < len(data.columns)-1; reflectionLineIndex++ {
+
+		if found, ok := getReflectionSmudges(data, reflectionLineIndex, true, smudges); ok && len(found) == smudges {
+
+			reflections = append(reflections, newReflection(data, Vertical, reflectionLineIndex+1, found))
+		}
+	}
 
 	for reflectionLineIndex := 0; reflectionLineIndex < len(data.rows)-1; reflectionLineIndex++ {
 
-		if checkHorizontalReflection(data, reflectionLineIndex, false, smudges) {
+		if found, ok := getReflectionSmudges(data, reflectionLineIndex, false, smudges); ok && len(found) == smudges {
 
-			sum += int64(reflectionLineIndex + 1)
+			reflections = append(reflections, newReflection(data, Horizontal, reflectionLineIndex+1, found))
 		}
 	}
 
-	return sum
+	return reflections
 }
 
-func checkHorizontalReflection(data Data, reflectionLineIndex int, isColumn bool, smudges int) bool {
+// collects the mismatching cells, gives up as soon as there are more than maxSmudges
+func getReflectionSmudges(data Data, reflectionLineIndex int, isColumn bool, maxSmudges int) ([]Smudge, bool) {
 
 	items := data.rows
 
@@ -57,7 +85,7 @@ func checkHorizontalReflection(data Data, reflectionLineIndex int, isColumn bool
 	topIndex := reflectionLineIndex
 	bottomIndex := reflectionLineIndex + 1
 
-	foundSmudges := 0
+	var foundSmudges []Smudge
 
 	for {
 		if topIndex < 0 || bottomIndex > len(items)-1 {
@@ -65,19 +93,24 @@ func checkHorizontalReflection(data Data, reflectionLineIndex int, isColumn bool
 			break
 		}
 
-		if items[topIndex] != items[bottomIndex] {
+		difference := bitgrid.Xor(items[topIndex], items[bottomIndex])
 
-			for charIndex := 0; charIndex < len(items[topIndex]); charIndex++ {
+		if difference.Count() > 0 {
 
-				if items[topIndex][charIndex] != items[bottomIndex][charIndex] {
+			if len(foundSmudges)+difference.Count() > maxSmudges {
 
-					foundSmudges++
-				}
+				return nil, false
 			}
 
-			if foundSmudges > smudges {
+			for _, charIndex := range difference.Ones() {
 
-				return false
+				if isColumn {
+
+					foundSmudges = append(foundSmudges, Smudge{x: topIndex, y: charIndex, mirrorX: bottomIndex, mirrorY: charIndex})
+				} else {
+
+					foundSmudges = append(foundSmudges, Smudge{x: charIndex, y: topIndex, mirrorX: charIndex, mirrorY: bottomIndex})
+				}
 			}
 		}
 
@@ -85,76 +118,99 @@ func checkHorizontalReflection(data Data, reflectionLineIndex int, isColumn bool
 		bottomIndex++
 	}
 
-	if foundSmudges == smudges {
-
-		return true
-	} else {
-
-		return false
-	}
+	return foundSmudges, true
 }
 
-func parseData(input string) Data {
+// fixes every smudge by flipping the cell on the top or left side of the axis
+func newReflection(data Data, axis Axis, index int, smudges []Smudge) Reflection {
 
-	var data Data
+	corrected := make([][]byte, len(data.lines))
 
-	lines := strings.Split(strings.ReplaceAll(input, "\r", ""), "\n")
+	for y, row := range data.lines {
 
-	for _, line := range lines {
-
-		data.rows = append(data.rows, line)
+		corrected[y] = []byte(row)
 	}
 
-	for x := 0; x < len(lines[0]); x++ {
+	for _, smudge := range smudges {
+
+		if corrected[smudge.y][smudge.x] == '#' {
 
-		line := ""
-		for y := 0; y < len(lines); y++ {
+			corrected[smudge.y][smudge.x] = '.'
+		} else {
 
-			line = line + string(lines[y][x])
+			corrected[smudge.y][smudge.x] = '#'
 		}
+	}
 
-		data.columns = append(data.columns, line)
+	reflection := Reflection{axis: axis, index: index, smudges: smudges}
+
+	for _, row := range corrected {
+
+		reflection.corrected = append(reflection.corrected, string(row))
 	}
 
-	return data
+	return reflection
 }
 
-func Part1(input string) string {
+func analysePatterns(content string, smudges int) []PatternResult {
 
-	content := GetContent(input)
+	var results []PatternResult
 
 	chunks := strings.Split(strings.ReplaceAll(content, "\r", ""), "\n\n")
 
+	for index, chunk := range chunks {
+
+		results = append(results, PatternResult{
+			pattern:     index,
+			reflections: findReflections(parseData(chunk), smudges),
+		})
+	}
+
+	return results
+}
+
+func summarize(results []PatternResult) int64 {
+
 	sum := int64(0)
 
-	for _, chunk := range chunks {
+	for _, result := range results {
+
+		for _, reflection := range result.reflections {
+
+			if reflection.axis == Horizontal {
 
-		data := parseData(chunk)
+				sum += int64(reflection.index) * 100
+			} else {
 
-		sum += getNumberOfLinesVertical(data, 0)
-		sum += getNumberOfLinesHorizontal(data, 0) * 100
+				sum += int64(reflection.index)
+			}
+		}
 	}
 
-	return strconv.FormatInt(sum, 10)
+	return sum
 }
 
-func Part2(input string) string {
+func parseData(input string) Data {
 
-	content := GetContent(input)
+	lines := strings.Split(strings.ReplaceAll(input, "\r", ""), "\n")
 
-	chunks := strings.Split(strings.ReplaceAll(content, "\r", ""), "\n\n")
+	grid := bitgrid.Parse(lines, '#')
 
-	sum := int64(0)
+	return Data{lines: lines, rows: grid.Rows, columns: grid.Columns}
+}
 
-	for _, chunk := range chunks {
+func Part1(input string) string {
 
-		data := parseData(chunk)
+	content := GetContent(input)
 
-		sum += getNumberOfLinesVertical(data, 1)
-		sum += getNumberOfLinesHorizontal(data, 1) * 100
-	}
+	return strconv.FormatInt(summarize(analysePatterns(content, 0)), 10)
+}
+
+func Part2(input string) string {
+
+	content := GetContent(input)
 
-	return strconv.FormatInt(sum, 10)
+	return strconv.FormatInt(summarize(analysePatterns(content, 1)), 10)
 }
 
 func GetContent(filepath string) string {
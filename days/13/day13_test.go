@@ -11,6 +11,26 @@ var P1_OUT_TEST = []string{"405"}
 var P2_IN_TEST = []string{fmt.Sprintf("test/%s/in01.txt", DAY)}
 var P2_OUT_TEST = []string{"400"}
 
+func TestAnalysePatterns(t *testing.T) {
+
+	testIn := P2_IN_TEST[0]
+	testOut := []string{
+		"[Reflection(axis=2, index=3, smudges=[{0 0 0 5}], corrected=[]string{\"..##..##.\", \"..#.##.#.\", \"##......#\", \"##......#\", \"..#.##.#.\", \"..##..##.\", \"#.#.##.#.\"})]",
+		"[Reflection(axis=2, index=1, smudges=[{4 0 4 1}], corrected=[]string{\"#....#..#\", \"#....#..#\", \"..##..###\", \"#####.##.\", \"#####.##.\", \"..##..###\", \"#....#..#\"})]",
+	}
+
+	for index, result := range analysePatterns(GetContent(testIn), 1) {
+
+		assert("analysePatterns", testIn, testOut[index], fmt.Sprintf("%v", result.reflections), t)
+	}
+
+	// a symmetric pattern reflects along several axes
+	testIn = "##\n##"
+	reflections := findReflections(parseData(testIn), 0)
+
+	assert("findReflections", testIn, "2", fmt.Sprintf("%d", len(reflections)), t)
+}
+
 func TestPart1(t *testing.T) {
 
 	for index, element := range P1_IN_TEST {
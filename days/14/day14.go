@@ -1,185 +1,189 @@
 package main
 
 import (
+	"days/24/bitgrid"
+	"days/24/cycle"
 	"fmt"
 	"log"
-	"math"
 	"os"
-	"slices"
 	"strconv"
 	"strings"
 )
 
 const DAY = "14"
 
-type Game struct {
-	columns []string
-}
+type TiltDirection int
+
+const (
+	North TiltDirection = iota + 1
+	East
+	South
+	West
+)
 
-func parseGame(input string) Game {
+type Platform struct {
+	rocks    []bitgrid.Bits
+	wallRows []bitgrid.Bits
+	width    int
+	height   int
+	// scratch row for east and west tilts
+	packed bitgrid.Bits
+}
 
-	var game Game
+func parsePlatform(input string) Platform {
 
 	lines := strings.Split(strings.ReplaceAll(input, "\r", ""), "\n")
 
-	game.columns = lines
-	rotateGameClockwise(&game)
-
-	slices.Reverse(game.columns)
+	rocks := bitgrid.Parse(lines, 'O')
+	walls := bitgrid.Parse(lines, '#')
 
-	return game
+	return Platform{
+		rocks:    rocks.Rows,
+		wallRows: walls.Rows,
+		width:    rocks.Width,
+		height:   rocks.Height,
+		packed:   bitgrid.NewBits(rocks.Width),
+	}
 }
 
-func rotateGameClockwise(game *Game) {
+func parseTiltSequence(input string) ([]TiltDirection, error) {
 
-	var newColumns []string
+	var sequence []TiltDirection
 
-	for y := len(game.columns[0]) - 1; y >= 0; y-- {
+	for _, element := range strings.Split(input, ",") {
 
-		newColumn := ""
-
-		for x := 0; x < len(game.columns); x++ {
-
-			newColumn += string(game.columns[x][y])
+		switch strings.TrimSpace(element) {
+		case "N":
+			sequence = append(sequence, North)
+		case "E":
+			sequence = append(sequence, East)
+		case "S":
+			sequence = append(sequence, South)
+		case "W":
+			sequence = append(sequence, West)
+		default:
+			return nil, fmt.Errorf("unknown tilt direction '%s' in sequence '%s'", element, input)
 		}
-
-		newColumns = append(newColumns, newColumn)
 	}
 
-	game.columns = newColumns
+	return sequence, nil
 }
 
-func getRockChunksPerColumn(game Game) [][]int {
+// walls never move, so clones share them
+func clonePlatform(platform Platform) Platform {
 
-	var rockChunksPerColumn [][]int
+	clone := platform
+	clone.rocks = make([]bitgrid.Bits, len(platform.rocks))
+	clone.packed = bitgrid.NewBits(platform.width)
 
-	for _, column := range game.columns {
+	for index, row := range platform.rocks {
 
-		var rockChunks []int
-		currentRockCount := 0
-
-		for i := 0; i < len(column); i++ {
+		clone.rocks[index] = row.Clone()
+	}
 
-			if string(column[i]) == "O" {
+	return clone
+}
 
-				currentRockCount++
+func tiltPlatform(platform *Platform, direction TiltDirection) {
 
-			} else if string(column[i]) == "#" {
+	switch direction {
+	case North, South:
+		bitgrid.Settle(platform.rocks, platform.wallRows, direction == South)
+	case East, West:
+		for y := range platform.rocks {
 
-				rockChunks = append(rockChunks, currentRockCount)
-				currentRockCount = 0
-			}
+			bitgrid.PackInto(platform.packed, platform.rocks[y], platform.wallRows[y], platform.width, direction == East)
+			copy(platform.rocks[y], platform.packed)
 		}
+	}
+}
 
-		rockChunks = append(rockChunks, currentRockCount)
+func runTiltSequence(platform *Platform, sequence []TiltDirection) {
 
-		rockChunksPerColumn = append(rockChunksPerColumn, rockChunks)
-	}
+	for _, direction := range sequence {
 
-	return rockChunksPerColumn
+		tiltPlatform(platform, direction)
+	}
 }
 
-func tiltGameNorth(game *Game) {
+func getTotalLoad(platform Platform) int {
 
-	rocksInColumn := getRockChunksPerColumn(*game)
-
-	var tiltedColumns []string
+	sum := 0
 
-	for columnIndex, column := range game.columns {
+	for y, row := range platform.rocks {
 
-		tiltedColumn := ""
+		sum += row.Count() * (platform.height - y)
+	}
 
-		rockChunk := rocksInColumn[columnIndex]
-		rockChunkIndex := 0
-		placedRocks := 0
+	return sum
+}
 
-		for i := 0; i < len(column); i++ {
+func stringify(platform Platform) string {
 
-			if rockChunkIndex == len(rockChunk) {
+	var builder strings.Builder
 
-				tiltedColumn += column[i:]
-				break
-			}
+	for y := 0; y < platform.height; y++ {
 
-			if string(column[i]) == "." || string(column[i]) == "O" {
+		for x := 0; x < platform.width; x++ {
 
-				if placedRocks < rockChunk[rockChunkIndex] {
+			if platform.rocks[y].Has(x) {
 
-					tiltedColumn += "O"
-					placedRocks++
-				} else {
+				builder.WriteByte('O')
+			} else if platform.wallRows[y].Has(x) {
 
-					tiltedColumn += "."
-				}
-			} else if string(column[i]) == "#" {
+				builder.WriteByte('#')
+			} else {
 
-				tiltedColumn += "#"
-				rockChunkIndex++
-				placedRocks = 0
+				builder.WriteByte('.')
 			}
 		}
 
-		tiltedColumns = append(tiltedColumns, tiltedColumn)
+		builder.WriteByte('\n')
 	}
 
-	game.columns = tiltedColumns
+	return builder.String()
 }
 
-func tiltCycle(game *Game) {
-
-	for i := 0; i < 4; i++ {
-
-		tiltGameNorth(game)
-		rotateGameClockwise(game)
-	}
-}
+// identifies the rock positions without rendering the whole platform
+func getPlatformKey(platform Platform) string {
 
-func getTotalLoad(game Game) int {
+	var builder strings.Builder
 
-	sum := 0
+	for _, row := range platform.rocks {
 
-	for _, column := range game.columns {
+		for _, word := range row {
 
-		for y := 0; y < len(column); y++ {
-
-			if string(column[y]) == "O" {
-
-				score := len(column) - y
-				sum += score
-			}
+			builder.WriteString(strconv.FormatUint(word, 36))
+			builder.WriteByte(',')
 		}
 	}
 
-	return sum
+	return builder.String()
 }
 
-func checkForLoop(loads []int, games []string, cycleIndices []int) (bool, int) {
-
-	for index := len(loads) - 2; index >= 0; index-- {
+func fastForward(platform Platform, sequence []TiltDirection, iterations int) (Platform, cycle.Cycle) {
 
-		if loads[index] == loads[len(loads)-1] && games[index] == games[len(loads)-1] {
+	step := func(current Platform) Platform {
 
-			return true, cycleIndices[len(cycleIndices)-1] - cycleIndices[index]
-		}
+		next := clonePlatform(current)
+		runTiltSequence(&next, sequence)
+		return next
 	}
 
-	return false, 0
-}
-
-func stringify(game Game) string {
+	platformCycle := cycle.Find(platform, step, cycle.ByKey(getPlatformKey))
 
-	return strings.Join(game.columns, "\n")
+	return cycle.StateAt(platform, step, platformCycle, iterations), platformCycle
 }
 
 func Part1(input string) string {
 
 	content := GetContent(input)
 
-	game := parseGame(content)
+	platform := parsePlatform(content)
 
-	tiltGameNorth(&game)
+	tiltPlatform(&platform, North)
 
-	sum := getTotalLoad(game)
+	sum := getTotalLoad(platform)
 
 	return strconv.Itoa(sum)
 }
@@ -190,30 +194,18 @@ func Part2(input string) string {
 
 	maxCycles := 1000000000
 
-	game := parseGame(content)
-
-	var loads []int
-	var cycleIndices []int
-	var games []string
-
-	for i := 0; i < maxCycles; i++ {
-
-		tiltCycle(&game)
+	platform := parsePlatform(content)
 
-		loads = append(loads, getTotalLoad(game))
-		cycleIndices = append(cycleIndices, i)
-		games = append(games, stringify(game))
+	sequence, err := parseTiltSequence("N,W,S,E")
 
-		foundLoop, deltaIndex := checkForLoop(loads, games, cycleIndices)
-
-		if foundLoop {
+	if err != nil {
 
-			increase := int(math.Floor(float64((maxCycles-1-i)/deltaIndex))) * deltaIndex
-			i += increase
-		}
+		panic(err)
 	}
 
-	sum := getTotalLoad(game)
+	platform, _ = fastForward(platform, sequence, maxCycles)
+
+	sum := getTotalLoad(platform)
 
 	return strconv.Itoa(sum)
 }
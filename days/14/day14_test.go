@@ -11,6 +11,39 @@ var P1_OUT_TEST = []string{"136"}
 var P2_IN_TEST = []string{fmt.Sprintf("test/%s/in01.txt", DAY)}
 var P2_OUT_TEST = []string{"64"}
 
+func TestTiltPlatform(t *testing.T) {
+
+	testIn := "O.#\n.O.\n#.O"
+	testOut := map[TiltDirection]string{
+		North: "OO#\n..O\n#..\n",
+		East:  ".O#\n..O\n#.O\n",
+		South: "..#\nO..\n#OO\n",
+		West:  "O.#\nO..\n#O.\n",
+	}
+
+	for direction, expected := range testOut {
+
+		platform := parsePlatform(testIn)
+		tiltPlatform(&platform, direction)
+		assert("tiltPlatform", fmt.Sprintf("%d", direction), expected, stringify(platform), t)
+	}
+}
+
+func TestFastForward(t *testing.T) {
+
+	testIn := P2_IN_TEST[0]
+	sequence, _ := parseTiltSequence("N,W,S,E")
+
+	_, platformCycle := fastForward(parsePlatform(GetContent(testIn)), sequence, 1000000000)
+
+	assert("fastForward", testIn, "3 7", fmt.Sprintf("%d %d", platformCycle.Start, platformCycle.Length), t)
+
+	if _, err := parseTiltSequence("N,X"); err == nil {
+
+		t.Errorf("parseTiltSequence(N,X) expected an error")
+	}
+}
+
 func TestPart1(t *testing.T) {
 
 	for index, element := range P1_IN_TEST {
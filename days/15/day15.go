@@ -1,25 +1,47 @@
 package main
 
 import (
+	"days/24/holidayhash"
 	"fmt"
+	"io"
 	"log"
-	"math"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 )
 
 const DAY = "15"
 
-type Boxes struct {
-	numbersToBox map[int]Box
+type Operation byte
+
+const (
+	Remove Operation = '-'
+	Insert Operation = '='
+)
+
+type Instruction struct {
+	step        string
+	label       string
+	operation   Operation
+	focalLength int
 }
 
+type Lens struct {
+	label       string
+	focalLength int
+}
+
+// lenses are kept in insertion order, replacing a lens keeps its slot
 type Box struct {
-	// contains a mapping of at least the labels in the box to their focal lengths
-	labelsToFocalLengths map[string]int
-	// contains the sorted labels in the box separated by comma
-	sortedLabelsCommaSeperated string
+	lenses []Lens
+}
+
+type Boxes [256]Box
+
+type TraceStep struct {
+	instruction Instruction
+	boxes       Boxes
 }
 
 func sumHash(input []string) int {
@@ -36,74 +58,168 @@ func sumHash(input []string) int {
 
 func hash(input string) int {
 
-	currentValue := 0
+	return holidayhash.String(input)
+}
+
+func parseInput(input string) []string {
+
+	return strings.Split(strings.ReplaceAll(strings.ReplaceAll(strings.ReplaceAll(input, "\r", ""), "\n", ""), " ", ""), ",")
+}
+
+func parseInstruction(step string) (Instruction, error) {
+
+	instruction := Instruction{step: step}
+
+	index := strings.IndexAny(step, "-=")
 
-	for _, character := range input {
+	if index <= 0 {
 
-		currentValue += int(character)
-		currentValue *= 17
-		currentValue = int(math.Mod(float64(currentValue), 256))
+		return instruction, fmt.Errorf("step '%s' has no label followed by '-' or '='", step)
 	}
 
-	return currentValue
+	instruction.label = step[:index]
+	instruction.operation = Operation(step[index])
+
+	if instruction.operation == Remove {
+
+		if index != len(step)-1 {
+
+			return instruction, fmt.Errorf("step '%s' has trailing characters after '-'", step)
+		}
+
+		return instruction, nil
+	}
+
+	focalLength, err := strconv.Atoi(step[index+1:])
+
+	if err != nil {
+
+		return instruction, fmt.Errorf("step '%s' has an invalid focal length: %w", step, err)
+	}
+
+	instruction.focalLength = focalLength
+
+	return instruction, nil
 }
 
-func parseInput(input string) []string {
+func parseInstructions(steps []string) ([]Instruction, error) {
 
-	return strings.Split(strings.ReplaceAll(strings.ReplaceAll(strings.ReplaceAll(input, "\r", ""), "\n", ""), " ", ""), ",")
+	var instructions []Instruction
+
+	for _, step := range steps {
+
+		instruction, err := parseInstruction(step)
+
+		if err != nil {
+
+			return nil, err
+		}
+
+		instructions = append(instructions, instruction)
+	}
+
+	return instructions, nil
 }
 
-func applyLens(lens string, boxes *Boxes) {
+func getLensIndex(box Box, label string) int {
 
-	// label is everything before "-" or "="
-	label := strings.Split(strings.Split(lens, "=")[0], "-")[0]
+	return slices.IndexFunc(box.lenses, func(lens Lens) bool {
 
-	// command is the first character after the label
-	command := lens[len(label) : len(label)+1]
+		return lens.label == label
+	})
+}
 
-	hash := hash(label)
+func applyInstruction(instruction Instruction, boxes *Boxes) {
 
-	box, boxExists := boxes.numbersToBox[hash]
+	box := &boxes[hash(instruction.label)]
+	index := getLensIndex(*box, instruction.label)
 
-	if command == "-" {
+	switch instruction.operation {
+	case Remove:
+		if index >= 0 {
+
+			box.lenses = slices.Delete(box.lenses, index, index+1)
+		}
+	case Insert:
+		if index >= 0 {
 
-		if boxExists {
+			box.lenses[index].focalLength = instruction.focalLength
+		} else {
 
-			box.sortedLabelsCommaSeperated = strings.Replace(box.sortedLabelsCommaSeperated, label+",", "", 1)
+			box.lenses = append(box.lenses, Lens{label: instruction.label, focalLength: instruction.focalLength})
 		}
+	}
+}
 
-	} else if command == "=" {
+func cloneBoxes(boxes Boxes) Boxes {
 
-		focalLength := stringToNumber(lens[len(lens)-1:])
+	clone := boxes
 
-		if !boxExists {
+	for index := range clone {
 
-			box = Box{make(map[string]int), ""}
-		}
+		clone[index].lenses = slices.Clone(boxes[index].lenses)
+	}
+
+	return clone
+}
 
-		if box.labelsToFocalLengths == nil {
+func simulate(instructions []Instruction, withTrace bool) (Boxes, []TraceStep) {
 
-			box.labelsToFocalLengths = make(map[string]int)
+	var boxes Boxes
+	var trace []TraceStep
+
+	for _, instruction := range instructions {
+
+		applyInstruction(instruction, &boxes)
+
+		if withTrace {
+
+			trace = append(trace, TraceStep{instruction: instruction, boxes: cloneBoxes(boxes)})
 		}
+	}
+
+	return boxes, trace
+}
+
+// prints the trace the way the puzzle does, listing only boxes that contain lenses
+func writeTrace(writer io.Writer, trace []TraceStep) error {
+
+	var builder strings.Builder
+
+	for _, step := range trace {
+
+		builder.WriteString(fmt.Sprintf("After \"%s\":\n", step.instruction.step))
+
+		for boxNumber, box := range step.boxes {
+
+			if len(box.lenses) == 0 {
+
+				continue
+			}
 
-		lensExists := strings.Contains(box.sortedLabelsCommaSeperated, label)
+			builder.WriteString(fmt.Sprintf("Box %d:", boxNumber))
 
-		box.labelsToFocalLengths[label] = int(focalLength)
+			for _, lens := range box.lenses {
 
-		if !lensExists {
+				builder.WriteString(fmt.Sprintf(" [%s %d]", lens.label, lens.focalLength))
+			}
 
-			box.sortedLabelsCommaSeperated += label + ","
+			builder.WriteString("\n")
 		}
+
+		builder.WriteString("\n")
 	}
 
-	boxes.numbersToBox[hash] = box
+	_, err := io.WriteString(writer, builder.String())
+
+	return err
 }
 
 func getFocussingPower(boxes Boxes) int {
 
 	sum := 0
 
-	for boxNumber, box := range boxes.numbersToBox {
+	for boxNumber, box := range boxes {
 
 		sum += getFocussingPowerBox(box, boxNumber)
 	}
@@ -115,17 +231,9 @@ func getFocussingPowerBox(box Box, boxNumber int) int {
 
 	sum := 0
 
-	if len(box.sortedLabelsCommaSeperated) > 0 {
-
-		for lensIndex, lens := range strings.Split(box.sortedLabelsCommaSeperated[:len(box.sortedLabelsCommaSeperated)-1], ",") {
-
-			focalLength, lensExists := box.labelsToFocalLengths[lens]
-
-			if lensExists {
+	for lensIndex, lens := range box.lenses {
 
-				sum += (1 + boxNumber) * (1 + lensIndex) * focalLength
-			}
-		}
+		sum += (1 + boxNumber) * (1 + lensIndex) * lens.focalLength
 	}
 
 	return sum
@@ -144,15 +252,15 @@ func Part2(input string) string {
 
 	content := GetContent(input)
 
-	sequence := parseInput(content)
-
-	boxes := Boxes{numbersToBox: make(map[int]Box)}
+	instructions, err := parseInstructions(parseInput(content))
 
-	for _, lens := range sequence {
+	if err != nil {
 
-		applyLens(lens, &boxes)
+		panic(err)
 	}
 
+	boxes, _ := simulate(instructions, false)
+
 	result := getFocussingPower(boxes)
 
 	return strconv.Itoa(result)
@@ -169,18 +277,6 @@ func GetContent(filepath string) string {
 	return string(content)
 }
 
-func stringToNumber(s string) int64 {
-
-	number, err := strconv.ParseInt(s, 10, 64)
-
-	if err != nil {
-
-		panic(err)
-	}
-
-	return number
-}
-
 func main() {
 
 	fmt.Println(fmt.Sprintf("Part 1: %s", Part1(fmt.Sprintf("input/%s/in.txt", DAY))))
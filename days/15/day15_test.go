@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -21,6 +22,44 @@ func TestHash(t *testing.T) {
 	assert("hash", testIn, fmt.Sprintf("%d", testOut), fmt.Sprintf("%d", result), t)
 }
 
+func TestSimulate(t *testing.T) {
+
+	// "ah" is a prefix of "ahp" and both land in box 105, focal lengths may have several digits
+	testIn := []string{"ahp=3", "ah=12", "ah-", "ahp=10"}
+	testOut := "[{ahp 10}]"
+
+	for _, label := range []string{"ahp", "ah"} {
+
+		assert("hash", label, "105", fmt.Sprintf("%d", hash(label)), t)
+	}
+
+	instructions, err := parseInstructions(testIn)
+
+	if err != nil {
+
+		t.Fatal(err)
+	}
+
+	boxes, trace := simulate(instructions, true)
+
+	assert("simulate", strings.Join(testIn, ","), testOut, fmt.Sprintf("%v", boxes[105].lenses), t)
+	assert("simulate", strings.Join(testIn, ","), "[{ahp 3} {ah 12}]", fmt.Sprintf("%v", trace[1].boxes[105].lenses), t)
+
+	var builder strings.Builder
+
+	if err := writeTrace(&builder, trace[:2]); err != nil {
+
+		t.Fatal(err)
+	}
+
+	assert("writeTrace", strings.Join(testIn, ","), "After \"ahp=3\":\nBox 105: [ahp 3]\n\nAfter \"ah=12\":\nBox 105: [ahp 3] [ah 12]\n\n", builder.String(), t)
+
+	if _, err := parseInstruction("ah=x"); err == nil {
+
+		t.Errorf("parseInstruction(ah=x) expected an error")
+	}
+}
+
 func TestPart1(t *testing.T) {
 
 	for index, element := range P1_IN_TEST {
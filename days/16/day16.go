@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"slices"
@@ -43,6 +44,20 @@ type Point struct {
 	positionY int
 }
 
+type RenderMode int
+
+const (
+	RenderEnergized RenderMode = iota + 1
+	RenderBeams
+)
+
+const (
+	ansiReset  = "\033[0m"
+	ansiYellow = "\033[1;33m"
+	ansiCyan   = "\033[36m"
+	ansiGray   = "\033[90m"
+)
+
 func parseGame(input string) Game {
 
 	lines := strings.Split(strings.ReplaceAll(input, "\r", ""), "\n")
@@ -240,6 +255,96 @@ func isTileEnergized(tile Tile) bool {
 	return len(tile.outgoingBeams) > 0
 }
 
+func renderTile(tile Tile, mode RenderMode) string {
+
+	if mode == RenderEnergized {
+
+		if isTileEnergized(tile) {
+
+			return "#"
+		}
+		return "."
+	}
+
+	// mirrors and splitters are drawn as they are, like in the puzzle illustrations
+	if tile.character != "." || !isTileEnergized(tile) {
+
+		return tile.character
+	}
+
+	if len(tile.outgoingBeams) > 1 {
+
+		return strconv.Itoa(len(tile.outgoingBeams))
+	}
+
+	switch tile.outgoingBeams[0] {
+	case North:
+		return "^"
+	case East:
+		return ">"
+	case South:
+		return "v"
+	case West:
+		return "<"
+	}
+
+	return tile.character
+}
+
+func colorizeTile(tile Tile, rendered string) string {
+
+	if isTileEnergized(tile) {
+
+		return ansiYellow + rendered + ansiReset
+	}
+
+	if tile.character != "." {
+
+		return ansiCyan + rendered + ansiReset
+	}
+
+	return ansiGray + rendered + ansiReset
+}
+
+func writeTiles(writer io.Writer, game Game, mode RenderMode, colored bool) error {
+
+	for y := 0; y < game.limitY; y++ {
+
+		var row strings.Builder
+
+		for x := 0; x < game.limitX; x++ {
+
+			rendered := renderTile(game.tiles[y][x], mode)
+
+			if colored {
+
+				rendered = colorizeTile(game.tiles[y][x], rendered)
+			}
+
+			row.WriteString(rendered)
+		}
+
+		row.WriteString("\n")
+
+		if _, err := io.WriteString(writer, row.String()); err != nil {
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+func renderTiles(game Game, mode RenderMode, colored bool) string {
+
+	var builder strings.Builder
+
+	// writing to a strings.Builder never fails
+	_ = writeTiles(&builder, game, mode, colored)
+
+	return builder.String()
+}
+
 func flushTiles(game *Game) {
 
 	for y := 0; y < game.limitY; y++ {
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -11,6 +12,52 @@ var P1_OUT_TEST = []string{"46"}
 var P2_IN_TEST = []string{fmt.Sprintf("test/%s/in01.txt", DAY)}
 var P2_OUT_TEST = []string{"51"}
 
+func TestRenderTiles(t *testing.T) {
+
+	testIn := P1_IN_TEST[0]
+	testOutEnergized := []string{
+		"######....",
+		".#...#....",
+		".#...#####",
+		".#...##...",
+		".#...##...",
+		".#...##...",
+		".#..####..",
+		"########..",
+		".#######..",
+		".#...#.#..",
+	}
+	testOutBeams := []string{
+		">|<<<\\....",
+		"|v-.\\^....",
+		".v...|->>>",
+		".v...v^.|.",
+		".v...v^...",
+		".v...v^..\\",
+		".v../2\\\\..",
+		"<->-/vv|..",
+		".|<<<2-|.\\",
+		".v//.|.v..",
+	}
+
+	game := parseGame(GetContent(testIn))
+	calculateBeam(Beam{
+		direction: East,
+		positionX: 0,
+		positionY: 0,
+	}, &game)
+
+	assert("renderTiles", testIn, strings.Join(testOutEnergized, "\n")+"\n", renderTiles(game, RenderEnergized, false), t)
+	assert("renderTiles", testIn, strings.Join(testOutBeams, "\n")+"\n", renderTiles(game, RenderBeams, false), t)
+
+	colored := renderTiles(game, RenderEnergized, true)
+
+	if !strings.HasPrefix(colored, ansiYellow+"#"+ansiReset) {
+
+		t.Errorf("renderTiles(%s) expected an ANSI highlighted first tile but received '%s'", testIn, colored)
+	}
+}
+
 func TestPart1(t *testing.T) {
 
 	for index, element := range P1_IN_TEST {
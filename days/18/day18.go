@@ -1,9 +1,11 @@
 package main
 
 import (
+	"days/24/polygon"
+	"errors"
 	"fmt"
+	"io"
 	"log"
-	"math"
 	"os"
 	"strconv"
 	"strings"
@@ -36,43 +38,183 @@ type Point struct {
 	positionY int
 }
 
-func parseGame(input string, isPartTwo bool) Game {
+// turns a dig plan into commands, errors name the offending line
+type PlanDecoder interface {
+	decode(input string) ([]DigCommand, error)
+}
+
+// "R 6 (#70c710)"
+type LetterDecoder struct{}
+
+// "R 6 (#70c710)" where the color holds the length in hex and the direction in its last digit
+type HexDecoder struct{}
+
+// "R6D5L2" with optional whitespace, the whole plan may be a single line
+type RunLengthDecoder struct{}
+
+var letterDirections = map[string]Direction{
+	"U": North,
+	"R": East,
+	"D": South,
+	"L": West,
+}
+
+var hexDirections = map[string]Direction{
+	"0": East,
+	"1": South,
+	"2": West,
+	"3": North,
+}
+
+func parseGame(input string, decoder PlanDecoder) (Game, error) {
 
 	var game Game
 
-	game.commands = parseCommands(input)
+	commands, err := decoder.decode(input)
+
+	if err != nil {
+
+		return game, err
+	}
+
+	game.commands = commands
 
-	return game
+	return game, nil
 }
 
-func parseCommands(input string) []DigCommand {
+func decodeLines(input string, decodeLine func(line string) (DigCommand, error)) ([]DigCommand, error) {
 
 	var commands []DigCommand
 
 	lines := strings.Split(strings.ReplaceAll(input, "\r", ""), "\n")
 
-	for _, line := range lines {
+	for index, line := range lines {
 
-		parts := strings.Split(line, " ")
+		command, err := decodeLine(line)
 
-		direction := North
-		switch parts[0] {
-		case `R`:
-			direction = East
-		case `D`:
-			direction = South
-		case `L`:
-			direction = West
+		if err != nil {
+
+			return nil, fmt.Errorf("line %d: %w", index+1, err)
 		}
 
-		commands = append(commands, DigCommand{
-			direction: direction,
-			length:    int(stringToNumber(parts[1])),
-			color:     parts[2],
-		})
+		commands = append(commands, command)
+	}
+
+	return commands, nil
+}
+
+func splitLine(line string) ([]string, error) {
+
+	parts := strings.Fields(line)
+
+	if len(parts) != 3 {
+
+		return nil, fmt.Errorf("expected direction, length and color but found '%s'", line)
 	}
 
-	return commands
+	return parts, nil
+}
+
+func (decoder LetterDecoder) decode(input string) ([]DigCommand, error) {
+
+	return decodeLines(input, func(line string) (DigCommand, error) {
+
+		parts, err := splitLine(line)
+
+		if err != nil {
+
+			return DigCommand{}, err
+		}
+
+		direction, known := letterDirections[parts[0]]
+
+		if !known {
+
+			return DigCommand{}, fmt.Errorf("unknown direction '%s'", parts[0])
+		}
+
+		length, err := strconv.Atoi(parts[1])
+
+		if err != nil {
+
+			return DigCommand{}, fmt.Errorf("invalid length '%s'", parts[1])
+		}
+
+		return DigCommand{direction: direction, length: length, color: parts[2]}, nil
+	})
+}
+
+func (decoder HexDecoder) decode(input string) ([]DigCommand, error) {
+
+	return decodeLines(input, func(line string) (DigCommand, error) {
+
+		parts, err := splitLine(line)
+
+		if err != nil {
+
+			return DigCommand{}, err
+		}
+
+		hex := strings.Trim(parts[2], "()#")
+
+		if len(hex) != 6 {
+
+			return DigCommand{}, fmt.Errorf("color '%s' does not hold six hex digits", parts[2])
+		}
+
+		direction, known := hexDirections[hex[5:]]
+
+		if !known {
+
+			return DigCommand{}, fmt.Errorf("unknown direction '%s' in color '%s'", hex[5:], parts[2])
+		}
+
+		length, err := strconv.ParseInt(hex[:5], 16, 64)
+
+		if err != nil {
+
+			return DigCommand{}, fmt.Errorf("invalid length '%s' in color '%s'", hex[:5], parts[2])
+		}
+
+		return DigCommand{direction: direction, length: int(length), color: parts[2]}, nil
+	})
+}
+
+func (decoder RunLengthDecoder) decode(input string) ([]DigCommand, error) {
+
+	var commands []DigCommand
+
+	for lineIndex, line := range strings.Split(strings.ReplaceAll(input, "\r", ""), "\n") {
+
+		line = strings.Join(strings.Fields(line), "")
+
+		for position := 0; position < len(line); {
+
+			direction, known := letterDirections[line[position:position+1]]
+
+			if !known {
+
+				return nil, fmt.Errorf("line %d: unknown direction '%c' at position %d", lineIndex+1, line[position], position+1)
+			}
+
+			end := position + 1
+
+			for end < len(line) && line[end] >= '0' && line[end] <= '9' {
+
+				end++
+			}
+
+			if end == position+1 {
+
+				return nil, fmt.Errorf("line %d: missing length after '%c' at position %d", lineIndex+1, line[position], position+1)
+			}
+
+			commands = append(commands, DigCommand{direction: direction, length: int(stringToNumber(line[position+1 : end]))})
+			position = end
+		}
+	}
+
+	return commands, nil
 }
 
 func calculateVertices(game *Game) {
@@ -101,73 +243,214 @@ func calculateVertices(game *Game) {
 	}
 }
 
-func parseColorsToCommands(game *Game) {
+func toPolygon(vertices []Point) []polygon.Point {
 
-	var newCommands []DigCommand
+	var points []polygon.Point
 
-	for _, command := range game.commands {
+	for _, vertex := range vertices {
 
-		direction, length := parseColor(command.color)
-
-		newCommands = append(newCommands, DigCommand{
-			direction: direction,
-			length:    length,
-			color:     "",
-		})
+		points = append(points, polygon.Point{X: vertex.positionX, Y: vertex.positionY})
 	}
 
-	game.commands = newCommands
+	return points
 }
 
-func parseColor(color string) (Direction, int) {
+// edge i of the trench is dug by command i
+func validateDigPlan(game Game) error {
+
+	err := polygon.Validate(toPolygon(game.vertices))
+
+	var intersection *polygon.IntersectionError
+
+	if errors.As(err, &intersection) {
+
+		return fmt.Errorf("dig commands %d and %d cross each other: %w", intersection.First, intersection.Second, err)
+	}
+
+	var notClosed *polygon.NotClosedError
 
-	length, _ := strconv.ParseInt(string(color[2:len(color)-2]), 16, 64)
+	if errors.As(err, &notClosed) {
 
-	switch string(color[len(color)-2 : len(color)-1]) {
-	case "0":
-		return East, int(length)
-	case "1":
-		return South, int(length)
-	case "2":
-		return West, int(length)
-	case "3":
-		return North, int(length)
+		return fmt.Errorf("dig plan does not return to its start: %w", err)
 	}
 
-	return North, 0
+	return err
 }
 
 func getFilledCount(game Game) int {
 
-	return int(ShoelaceFormula(game.vertices)) + int(Perimeter(game.vertices)/2) + 1
+	points := toPolygon(game.vertices)
+
+	return polygon.Interior(points) + polygon.Boundary(points)
+}
+
+const maxRenderedCells = 1000000
+
+func getBoundingBox(vertices []Point) (Point, Point) {
+
+	minimum, maximum := vertices[0], vertices[0]
+
+	for _, vertex := range vertices {
+
+		minimum.positionX = min(minimum.positionX, vertex.positionX)
+		minimum.positionY = min(minimum.positionY, vertex.positionY)
+		maximum.positionX = max(maximum.positionX, vertex.positionX)
+		maximum.positionY = max(maximum.positionY, vertex.positionY)
+	}
+
+	return minimum, maximum
+}
+
+// draws the trench as '#' and the dug out interior with fill, the vertices must be calculated already
+func renderLagoon(game Game, fill byte) (string, error) {
+
+	minimum, maximum := getBoundingBox(game.vertices)
+	width := maximum.positionX - minimum.positionX + 1
+	height := maximum.positionY - minimum.positionY + 1
+
+	if width*height > maxRenderedCells {
+
+		return "", fmt.Errorf("lagoon of %dx%d cells is too large to render", width, height)
+	}
+
+	grid := make([][]byte, height)
+
+	for y := range grid {
+
+		grid[y] = []byte(strings.Repeat(".", width))
+	}
+
+	for index := 0; index < len(game.vertices)-1; index++ {
+
+		from, to := game.vertices[index], game.vertices[index+1]
+
+		for y := min(from.positionY, to.positionY); y <= max(from.positionY, to.positionY); y++ {
+
+			for x := min(from.positionX, to.positionX); x <= max(from.positionX, to.positionX); x++ {
+
+				grid[y-minimum.positionY][x-minimum.positionX] = '#'
+			}
+		}
+	}
+
+	// a cell is inside if a ray to the right crosses an odd number of vertical trench segments
+	for y := 0; y < height; y++ {
+
+		for x := 0; x < width; x++ {
+
+			if grid[y][x] == '#' {
+
+				continue
+			}
+
+			crossings := 0
+
+			for index := 0; index < len(game.vertices)-1; index++ {
+
+				from, to := game.vertices[index], game.vertices[index+1]
+				segmentX := from.positionX - minimum.positionX
+				top := min(from.positionY, to.positionY) - minimum.positionY
+				bottom := max(from.positionY, to.positionY) - minimum.positionY
+
+				if from.positionX == to.positionX && segmentX > x && top <= y && y < bottom {
+
+					crossings++
+				}
+			}
+
+			if crossings%2 == 1 {
+
+				grid[y][x] = fill
+			}
+		}
+	}
+
+	var builder strings.Builder
+
+	for _, row := range grid {
+
+		builder.Write(row)
+		builder.WriteByte('\n')
+	}
+
+	return builder.String(), nil
 }
 
-func ShoelaceFormula(points []Point) float64 {
-	var sum float64
-	n := len(points)
-	for i := 0; i < n-1; i++ {
-		sum += float64(points[i].positionX*points[i+1].positionY - points[i+1].positionX*points[i].positionY)
+func getHexColor(color string) string {
+
+	hex := strings.Trim(color, "()")
+
+	if len(hex) != 7 || hex[0] != '#' {
+
+		return "black"
 	}
-	return math.Abs(sum / 2)
+
+	return hex
 }
 
-func Perimeter(points []Point) float64 {
-	var sum float64
-	n := len(points)
-	for i := 0; i < n-1; i++ {
-		sum += math.Abs(float64(points[i+1].positionX-points[i].positionX)) + math.Abs(float64(points[i+1].positionY-points[i].positionY))
+// draws every segment in its own color, the longer side of the image is scaled to maxSize pixels
+func writeSVG(writer io.Writer, game Game, maxSize int) error {
+
+	minimum, maximum := getBoundingBox(game.vertices)
+	width := maximum.positionX - minimum.positionX + 1
+	height := maximum.positionY - minimum.positionY + 1
+
+	scale := float64(maxSize) / float64(max(width, height))
+
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%.0f\" height=\"%.0f\" viewBox=\"%d %d %d %d\">\n",
+		max(1, float64(width)*scale), max(1, float64(height)*scale), minimum.positionX, minimum.positionY, width, height))
+
+	builder.WriteString("  <path fill=\"lightblue\" d=\"")
+
+	for index, vertex := range game.vertices {
+
+		command := "L"
+
+		if index == 0 {
+
+			command = "M"
+		}
+
+		builder.WriteString(fmt.Sprintf("%s%d %d ", command, vertex.positionX, vertex.positionY))
+	}
+
+	builder.WriteString("Z\"/>\n")
+
+	for index, command := range game.commands {
+
+		from, to := game.vertices[index], game.vertices[index+1]
+
+		builder.WriteString(fmt.Sprintf("  <line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"%s\" stroke-width=\"1\" vector-effect=\"non-scaling-stroke\"/>\n",
+			from.positionX, from.positionY, to.positionX, to.positionY, getHexColor(command.color)))
 	}
-	return sum
+
+	builder.WriteString("</svg>\n")
+
+	_, err := io.WriteString(writer, builder.String())
+
+	return err
 }
 
 func Part1(input string) string {
 
 	content := GetContent(input)
 
-	game := parseGame(content, false)
+	game, err := parseGame(content, LetterDecoder{})
+
+	if err != nil {
+
+		panic(err)
+	}
 
 	calculateVertices(&game)
 
+	if err = validateDigPlan(game); err != nil {
+
+		panic(err)
+	}
+
 	return strconv.Itoa(getFilledCount(game))
 }
 
@@ -175,12 +458,20 @@ func Part2(input string) string {
 
 	content := GetContent(input)
 
-	game := parseGame(content, true)
+	game, err := parseGame(content, HexDecoder{})
 
-	parseColorsToCommands(&game)
+	if err != nil {
+
+		panic(err)
+	}
 
 	calculateVertices(&game)
 
+	if err = validateDigPlan(game); err != nil {
+
+		panic(err)
+	}
+
 	return strconv.Itoa(getFilledCount(game))
 }
 
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -11,6 +12,135 @@ var P1_OUT_TEST = []string{"62"}
 var P2_IN_TEST = []string{fmt.Sprintf("test/%s/in01.txt", DAY)}
 var P2_OUT_TEST = []string{"952408144115"}
 
+func TestValidateDigPlan(t *testing.T) {
+
+	testIn := []string{
+		"R 2 (#000000)\nD 2 (#000000)\nL 2 (#000000)\nU 2 (#000000)",
+		"R 2 (#000000)\nD 2 (#000000)\nL 2 (#000000)",
+		"R 4 (#000000)\nD 2 (#000000)\nL 2 (#000000)\nU 4 (#000000)\nL 2 (#000000)\nD 2 (#000000)",
+	}
+	testOut := []string{
+		"<nil>",
+		"dig plan does not return to its start: path starts at (0, 0) but ends at (0, 2)",
+		"dig commands 0 and 3 cross each other",
+	}
+
+	for index, element := range testIn {
+
+		game, _ := parseGame(element, LetterDecoder{})
+		calculateVertices(&game)
+
+		received := fmt.Sprintf("%v", validateDigPlan(game))
+
+		if !strings.HasPrefix(received, testOut[index]) {
+
+			t.Errorf("validateDigPlan(%s) expected '%s' but received '%s'", element, testOut[index], received)
+		}
+	}
+}
+
+func TestRenderLagoon(t *testing.T) {
+
+	testIn := P1_IN_TEST[0]
+	testOut := []string{
+		"#######",
+		"#~~~~~#",
+		"###~~~#",
+		"..#~~~#",
+		"..#~~~#",
+		"###~###",
+		"#~~~#..",
+		"##~~###",
+		".#~~~~#",
+		".######",
+	}
+
+	game, _ := parseGame(GetContent(testIn), LetterDecoder{})
+	calculateVertices(&game)
+
+	received, err := renderLagoon(game, '~')
+
+	if err != nil {
+
+		t.Fatal(err)
+	}
+
+	assert("renderLagoon", testIn, strings.Join(testOut, "\n")+"\n", received, t)
+}
+
+func TestWriteSVG(t *testing.T) {
+
+	testIn := P2_IN_TEST[0]
+
+	game, _ := parseGame(GetContent(testIn), LetterDecoder{})
+	calculateVertices(&game)
+
+	var builder strings.Builder
+
+	if err := writeSVG(&builder, game, 100); err != nil {
+
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{`width="70" height="100" viewBox="0 0 7 10"`, `stroke="#70c710"`} {
+
+		if !strings.Contains(builder.String(), expected) {
+
+			t.Errorf("writeSVG(%s) expected '%s' in '%s'", testIn, expected, builder.String())
+		}
+	}
+
+	// the hex encoded plan is far too large to draw cell by cell, but the SVG is scaled down
+	game, _ = parseGame(GetContent(testIn), HexDecoder{})
+	calculateVertices(&game)
+
+	if _, err := renderLagoon(game, '#'); err == nil {
+
+		t.Errorf("renderLagoon(%s) expected an error for a huge lagoon", testIn)
+	}
+
+	builder.Reset()
+
+	if err := writeSVG(&builder, game, 100); err != nil || !strings.Contains(builder.String(), `height="100"`) {
+
+		t.Errorf("writeSVG(%s) expected a scaled down image but received '%s'", testIn, builder.String())
+	}
+}
+
+func TestDecoders(t *testing.T) {
+
+	testIn := []string{
+		"R 6 (#70c710)\nD 5 (#0dc571)",
+		"R 6 (#70c710)\nD 5 (#0dc571)",
+		"R6 D5\nL 2",
+		"R 6 (#70c710)\nX 5 (#0dc571)",
+		"R 6 (#70c710)\nD 5 (#0dc575)",
+		"R6D5\nL2Q1",
+	}
+	decoders := []PlanDecoder{LetterDecoder{}, HexDecoder{}, RunLengthDecoder{}, LetterDecoder{}, HexDecoder{}, RunLengthDecoder{}}
+	testOut := []string{
+		"[{2 6 (#70c710)} {3 5 (#0dc571)}]",
+		"[{2 461937 (#70c710)} {3 56407 (#0dc571)}]",
+		"[{2 6 } {3 5 } {4 2 }]",
+		"line 2: unknown direction 'X'",
+		"line 2: unknown direction '5' in color '(#0dc575)'",
+		"line 2: unknown direction 'Q' at position 3",
+	}
+
+	for index, element := range testIn {
+
+		game, err := parseGame(element, decoders[index])
+		received := fmt.Sprintf("%v", game.commands)
+
+		if err != nil {
+
+			received = err.Error()
+		}
+
+		assert("parseGame", element, testOut[index], received, t)
+	}
+}
+
 func TestPart1(t *testing.T) {
 
 	for index, element := range P1_IN_TEST {
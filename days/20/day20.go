@@ -1,6 +1,7 @@
 package main
 
 import (
+	"days/24/cycle"
 	"fmt"
 	"log"
 	"os"
@@ -156,6 +157,45 @@ func cloneMap(mapping map[string]Pulse) map[string]Pulse {
 	return result
 }
 
+func stringify(simulation Simulation) string {
+
+	var labels []string
+
+	for label := range simulation.modules {
+
+		labels = append(labels, label)
+	}
+
+	slices.Sort(labels)
+
+	var builder strings.Builder
+
+	for _, label := range labels {
+
+		module := simulation.modules[label]
+		builder.WriteString(fmt.Sprintf("%s:%t:", label, module.isOn))
+
+		for _, input := range module.input {
+
+			builder.WriteString(fmt.Sprintf("%t,", module.lastPulseReceivedByInput[input].isHigh))
+		}
+
+		builder.WriteString(";")
+	}
+
+	builder.WriteString(fmt.Sprintf("%d:%d:%v", simulation.highPulsesSent, simulation.lowPulsesSent, simulation.conjunctionsFiredLow))
+
+	return builder.String()
+}
+
+// presses the button in place, so only keys of earlier states may be kept
+func pressButton(simulation Simulation) Simulation {
+
+	processPulses(&simulation)
+
+	return simulation
+}
+
 func parseModule(line string) Module {
 
 	chunks := strings.Split(line, " -> ")
@@ -318,20 +358,13 @@ func Part1(input string) string {
 
 	limit := 1000
 
-	highPulsesSent := 0
-	lowPulsesSent := 0
-	runsCompleted := 0
-
-	for runsCompleted < limit {
+	// skip ahead if the modules return to an earlier configuration within the limit
+	pulsesSent, _ := cycle.SumsUntil(simulation, pressButton, stringify, limit, func(current Simulation) []int {
 
-		processPulses(&simulation)
-
-		highPulsesSent += simulation.highPulsesSent
-		lowPulsesSent += simulation.lowPulsesSent
-		runsCompleted += 1
-	}
+		return []int{current.lowPulsesSent, current.highPulsesSent}
+	})
 
-	return strconv.Itoa(lowPulsesSent * highPulsesSent)
+	return strconv.Itoa(pulsesSent[0] * pulsesSent[1])
 }
 
 func Part2(input string) string {
@@ -345,24 +378,26 @@ func Part2(input string) string {
 
 	simulation := parseSimulation(content)
 
-	runsCompleted := 0
-
-	for len(runsUntilOn) < len(conjunctionsFiringLow) {
-
-		processPulses(&simulation)
-
-		runsCompleted += 1
+	// stop once every conjunction fired, a repeating configuration means the missing ones never fire
+	_, repeated := cycle.Walk(simulation, pressButton, stringify, -1, func(runsCompleted int, current Simulation) bool {
 
 		for _, conjunctionRequired := range conjunctionsFiringLow {
 
 			if _, alreadyFound := runsUntilOn[conjunctionRequired]; !alreadyFound {
 
-				if slices.Contains(simulation.conjunctionsFiredLow, conjunctionRequired) {
+				if slices.Contains(current.conjunctionsFiredLow, conjunctionRequired) {
 
 					runsUntilOn[conjunctionRequired] = runsCompleted
 				}
 			}
 		}
+
+		return len(runsUntilOn) < len(conjunctionsFiringLow)
+	})
+
+	if repeated {
+
+		return "conjunctions never fire"
 	}
 
 	var runs []int
@@ -8,8 +8,8 @@ import (
 var P1_IN_TEST = []string{fmt.Sprintf("test/%s/in01.txt", DAY)}
 var P1_OUT_TEST = []string{"11687500"}
 
-var P2_IN_TEST = []string{}
-var P2_OUT_TEST = []string{}
+var P2_IN_TEST = []string{fmt.Sprintf("test/%s/in01.txt", DAY)}
+var P2_OUT_TEST = []string{"conjunctions never fire"}
 
 func TestPart1(t *testing.T) {
 
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"slices"
@@ -23,11 +26,6 @@ type Brick struct {
 	z Range
 }
 
-func stringify(brick Brick) string {
-
-	return fmt.Sprintf("(%#v,%#v,%#v)", brick.x, brick.y, brick.z)
-}
-
 func (r Range) String() string {
 
 	return fmt.Sprintf("%d-%d", r.lower, r.upper)
@@ -35,6 +33,48 @@ func (r Range) String() string {
 
 type Bricks []Brick
 
+type Plane int
+
+const (
+	XZ Plane = iota + 1
+	YZ
+)
+
+type VoxelBrick struct {
+	Id        int      `json:"id"`
+	Label     string   `json:"label"`
+	Unsafe    bool     `json:"unsafe"`
+	ChainSize int      `json:"chainSize"`
+	Supports  []int    `json:"supports"`
+	RestsOn   []int    `json:"restsOn"`
+	Voxels    [][3]int `json:"voxels"`
+}
+
+type VoxelExport struct {
+	Size   [3]int       `json:"size"`
+	Bricks []VoxelBrick `json:"bricks"`
+}
+
+type HeightMap struct {
+	// footprint covered by the map
+	x Range
+	y Range
+	// highest settled z and the index of the brick reaching it, -1 for the ground
+	heights []int
+	tops    []int
+}
+
+type SupportGraph struct {
+	// settled bricks, identified by their index
+	bricks Bricks
+	above  [][]int
+	below  [][]int
+	// immediate dominators, len(bricks) stands for the ground
+	dominators []int
+	// number of bricks that fall when the brick is removed
+	chainSizes []int
+}
+
 func parseBricks(input string) Bricks {
 
 	var bricks Bricks
@@ -103,21 +143,6 @@ func sortBricksByZ(bricks Bricks) Bricks {
 	return sortedBricks
 }
 
-func getOverlappingBricks(brick Brick, bricks Bricks) Bricks {
-
-	var result Bricks
-
-	for _, otherBrick := range bricks {
-
-		if overlapIgnoringZ(brick, otherBrick) && !bricksEqual(brick, otherBrick) {
-
-			result = append(result, otherBrick)
-		}
-	}
-
-	return result
-}
-
 func overlapIgnoringZ(b1 Brick, b2 Brick) bool {
 
 	return overlap1D(b1.x, b2.x) && overlap1D(b1.y, b2.y)
@@ -128,6 +153,7 @@ func overlap1D(r1 Range, r2 Range) bool {
 	return !(r1.lower > r2.upper || r1.upper < r2.lower)
 }
 
+// dropBricks is the straightforward quadratic settling, kept as a reference for settleBricks
 func dropBricks(bricks Bricks) (Bricks, int) {
 
 	sortedBricks := sortBricksByZ(bricks)
@@ -181,123 +207,484 @@ func dropBrick(brick Brick, droppedBricks Bricks) (Brick, bool) {
 	return newBrick, newBrick.z.lower != brick.z.lower
 }
 
-func getNumberOfSafeBricks(bricks Bricks) (int, Bricks) {
+func newHeightMap(bricks Bricks) HeightMap {
 
-	bricks = sortBricksByZ(bricks)
+	if len(bricks) == 0 {
 
-	count := 0
+		return HeightMap{}
+	}
+
+	heightMap := HeightMap{x: bricks[0].x, y: bricks[0].y}
 
-	var unsafeBricks Bricks
+	for _, brick := range bricks {
 
-	overlappingBricksAbove := make(map[string]Bricks)
-	overlappingBricksBelow := make(map[string]Bricks)
+		heightMap.x = Range{lower: min(heightMap.x.lower, brick.x.lower), upper: max(heightMap.x.upper, brick.x.upper)}
+		heightMap.y = Range{lower: min(heightMap.y.lower, brick.y.lower), upper: max(heightMap.y.upper, brick.y.upper)}
+	}
 
-	for index, brick := range bricks {
+	size := (heightMap.x.upper - heightMap.x.lower + 1) * (heightMap.y.upper - heightMap.y.lower + 1)
+	heightMap.heights = make([]int, size)
+	heightMap.tops = make([]int, size)
 
-		brickString := stringify(brick)
+	for index := range heightMap.tops {
 
-		overlappingBricksAbove[brickString] = getOverlappingBricks(brick, bricks[index+1:])
-		overlappingBricksBelow[brickString] = getOverlappingBricks(brick, bricks[:index])
+		heightMap.tops[index] = -1
 	}
 
-	for _, brick := range bricks {
+	return heightMap
+}
 
-		brickString := stringify(brick)
+func getCellIndex(heightMap HeightMap, x int, y int) int {
 
-		if above, _ := overlappingBricksAbove[brickString]; len(above) == 0 {
+	return (y-heightMap.y.lower)*(heightMap.x.upper-heightMap.x.lower+1) + x - heightMap.x.lower
+}
 
-			count++
+func settleBricks(bricks Bricks) (Bricks, [][]int, int) {
 
-		} else {
+	sortedBricks := sortBricksByZ(bricks)
+	heightMap := newHeightMap(sortedBricks)
 
-			isSafe := true
+	settledBricks := make(Bricks, len(sortedBricks))
+	below := make([][]int, len(sortedBricks))
+	numberOfDroppedBricks := 0
 
-			for _, aboveBrick := range overlappingBricksAbove[brickString] {
+	for index, brick := range sortedBricks {
 
-				if overlapIgnoringZ(brick, aboveBrick) && brick.z.upper == aboveBrick.z.lower-1 {
+		// find the highest point below the footprint
+		floor := 0
 
-					bricksBelowTheAbove, _ := overlappingBricksBelow[stringify(aboveBrick)]
+		for x := brick.x.lower; x <= brick.x.upper; x++ {
 
-					if len(bricksBelowTheAbove) == 1 {
+			for y := brick.y.lower; y <= brick.y.upper; y++ {
 
-						isSafe = false
-						unsafeBricks = append(unsafeBricks, brick)
-						break
-					}
+				floor = max(floor, heightMap.heights[getCellIndex(heightMap, x, y)])
+			}
+		}
 
-					isAnotherSupporting := false
+		settledBrick := brick
+		settledBrick.z = Range{lower: floor + 1, upper: floor + 1 + brick.z.upper - brick.z.lower}
 
-					for _, otherBrickBelow := range bricksBelowTheAbove {
+		for x := brick.x.lower; x <= brick.x.upper; x++ {
 
-						if !bricksEqual(brick, otherBrickBelow) && overlapIgnoringZ(otherBrickBelow, aboveBrick) && otherBrickBelow.z.upper == aboveBrick.z.lower-1 {
+			for y := brick.y.lower; y <= brick.y.upper; y++ {
 
-							isAnotherSupporting = true
-							break
-						}
-					}
+				cell := getCellIndex(heightMap, x, y)
 
-					if !isAnotherSupporting {
+				// every brick topping the highest point supports the settled brick
+				if top := heightMap.tops[cell]; heightMap.heights[cell] == floor && top >= 0 && !slices.Contains(below[index], top) {
 
-						isSafe = false
-						unsafeBricks = append(unsafeBricks, brick)
-						break
-					}
+					below[index] = append(below[index], top)
 				}
+
+				heightMap.heights[cell] = settledBrick.z.upper
+				heightMap.tops[cell] = index
 			}
+		}
+
+		slices.Sort(below[index])
+		settledBricks[index] = settledBrick
+
+		if settledBrick.z.lower != brick.z.lower {
+
+			numberOfDroppedBricks++
+		}
+	}
 
-			if isSafe {
+	return settledBricks, below, numberOfDroppedBricks
+}
 
-				count++
+func getSupportsPairwise(bricks Bricks) [][]int {
 
+	below := make([][]int, len(bricks))
+
+	for index, brick := range bricks {
+
+		for other := 0; other < index; other++ {
+
+			if overlapIgnoringZ(brick, bricks[other]) && bricks[other].z.upper == brick.z.lower-1 {
+
+				below[index] = append(below[index], other)
 			}
 		}
+	}
+
+	return below
+}
 
+func buildSupportGraph(bricks Bricks, below [][]int) SupportGraph {
+
+	graph := SupportGraph{
+		bricks:     bricks,
+		above:      make([][]int, len(bricks)),
+		below:      below,
+		dominators: make([]int, len(bricks)),
+		chainSizes: make([]int, len(bricks)),
+	}
+
+	// settled bricks can only rest on bricks that settled before them, so the indices are a topological order
+	for index := range bricks {
+
+		for _, other := range below[index] {
+
+			graph.above[other] = append(graph.above[other], index)
+		}
 	}
 
-	return count, unsafeBricks
+	computeDominators(&graph)
+
+	return graph
 }
 
-func Part1(input string) string {
+func computeDominators(graph *SupportGraph) {
 
-	content := GetContent(input)
+	ground := len(graph.bricks)
+	depths := make([]int, len(graph.bricks)+1)
 
-	bricks := parseBricks(content)
+	for index := range graph.bricks {
+
+		if len(graph.below[index]) == 0 {
+
+			graph.dominators[index] = ground
+		} else {
 
-	bricks, _ = dropBricks(bricks)
+			// the immediate dominator is the common dominator of all supporting bricks
+			dominator := graph.below[index][0]
 
-	numberOfSafeBricks, _ := getNumberOfSafeBricks(bricks)
+			for _, other := range graph.below[index][1:] {
+
+				dominator = intersectDominators(graph, depths, dominator, other)
+			}
+
+			graph.dominators[index] = dominator
+		}
 
-	return strconv.Itoa(numberOfSafeBricks)
+		depths[index] = depths[graph.dominators[index]] + 1
+	}
+
+	subtreeSizes := make([]int, len(graph.bricks)+1)
+
+	for index := len(graph.bricks) - 1; index >= 0; index-- {
+
+		subtreeSizes[index]++
+		subtreeSizes[graph.dominators[index]] += subtreeSizes[index]
+		graph.chainSizes[index] = subtreeSizes[index] - 1
+	}
 }
 
-func Part2(input string) string {
+func intersectDominators(graph *SupportGraph, depths []int, first int, second int) int {
 
-	content := GetContent(input)
+	for first != second {
 
-	bricks := parseBricks(content)
+		if depths[first] < depths[second] {
+
+			second = graph.dominators[second]
+		} else {
+
+			first = graph.dominators[first]
+		}
+	}
+
+	return first
+}
+
+func getNumberOfSafeBricks(graph SupportGraph) int {
+
+	count := 0
+
+	for _, size := range graph.chainSizes {
+
+		if size == 0 {
+
+			count++
+		}
+	}
 
-	bricks, _ = dropBricks(bricks)
+	return count
+}
+
+func getFallingBricks(graph SupportGraph, removed []int) []int {
+
+	var falling []int
+
+	gone := make([]bool, len(graph.bricks))
+
+	for _, brick := range removed {
+
+		gone[brick] = true
+	}
+
+	for index := range graph.bricks {
+
+		if gone[index] || len(graph.below[index]) == 0 {
+
+			continue
+		}
+
+		fallen := true
+
+		for _, other := range graph.below[index] {
+
+			if !gone[other] {
+
+				fallen = false
+				break
+			}
+		}
+
+		if fallen {
+
+			gone[index] = true
+			falling = append(falling, index)
+		}
+	}
+
+	return falling
+}
+
+func getRestingOn(graph SupportGraph, brick int) []int {
+
+	visited := make([]bool, len(graph.bricks))
+	stack := []int{brick}
+
+	for len(stack) > 0 {
+
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for _, other := range graph.below[current] {
+
+			if !visited[other] {
+
+				visited[other] = true
+				stack = append(stack, other)
+			}
+		}
+	}
 
-	_, unsafeBricks := getNumberOfSafeBricks(bricks)
+	var result []int
+
+	for index, isBelow := range visited {
+
+		if isBelow {
+
+			result = append(result, index)
+		}
+	}
+
+	return result
+}
+
+func getCriticalSupports(graph SupportGraph, brick int) []int {
+
+	// removing any of these bricks makes the given brick fall, nearest first
+	var result []int
+
+	for current := graph.dominators[brick]; current != len(graph.bricks); current = graph.dominators[current] {
+
+		result = append(result, current)
+	}
+
+	return result
+}
+
+func getTotalChainReaction(graph SupportGraph) int {
 
 	sum := 0
 
-	for _, brick := range unsafeBricks {
+	for _, size := range graph.chainSizes {
 
-		reducedBrickSet := make(Bricks, len(bricks))
-		copy(reducedBrickSet, bricks)
+		sum += size
+	}
+
+	return sum
+}
 
-		reducedBrickSet = slices.DeleteFunc(reducedBrickSet, func(currentBrick Brick) bool {
+// labels count like spreadsheet columns: A, ..., Z, AA, AB, ...
+func getLabel(index int) string {
 
-			return bricksEqual(brick, currentBrick)
-		})
+	label := ""
 
-		_, droppedBricks := dropBricks(reducedBrickSet)
+	for index++; index > 0; index = (index - 1) / 26 {
 
-		sum += droppedBricks
+		label = string(rune('A'+(index-1)%26)) + label
 	}
 
-	return strconv.Itoa(sum)
+	return label
+}
+
+// looks at the stack along the y or x axis like the puzzle does, cells hiding more than one brick show '?'.
+// Cells hold the labels of the JSON export, beyond 26 bricks they widen to the longest label and are separated by spaces.
+func renderProjection(bricks Bricks, plane Plane) string {
+
+	width, height := 0, 0
+
+	for _, brick := range bricks {
+
+		width = max(width, getHorizontal(brick, plane).upper+1)
+		height = max(height, brick.z.upper)
+	}
+
+	grid := make([][]int, height+1)
+
+	for z := range grid {
+
+		grid[z] = make([]int, width)
+
+		for column := range grid[z] {
+
+			grid[z][column] = -1
+		}
+	}
+
+	for index, brick := range bricks {
+
+		horizontal := getHorizontal(brick, plane)
+
+		for z := brick.z.lower; z <= brick.z.upper; z++ {
+
+			for column := horizontal.lower; column <= horizontal.upper; column++ {
+
+				if grid[z][column] == -1 || grid[z][column] == index {
+
+					grid[z][column] = index
+				} else {
+
+					grid[z][column] = -2
+				}
+			}
+		}
+	}
+
+	axis := "x"
+
+	if plane == YZ {
+
+		axis = "y"
+	}
+
+	cellWidth := len(getLabel(max(len(bricks)-1, 0)))
+	separator := ""
+
+	if cellWidth > 1 {
+
+		separator = " "
+	}
+
+	lineWidth := width*cellWidth + max(width-1, 0)*len(separator)
+	cells := make([]string, width)
+
+	var builder strings.Builder
+
+	builder.WriteString(strings.Repeat(" ", lineWidth/2) + axis + "\n")
+
+	for column := range cells {
+
+		cells[column] = fmt.Sprintf("%*d", cellWidth, column%10)
+	}
+
+	builder.WriteString(strings.Join(cells, separator) + "\n")
+
+	for z := height; z > 0; z-- {
+
+		for column, cell := range grid[z] {
+
+			switch cell {
+			case -1:
+				cells[column] = strings.Repeat(".", cellWidth)
+			case -2:
+				cells[column] = strings.Repeat("?", cellWidth)
+			default:
+				cells[column] = fmt.Sprintf("%*s", cellWidth, getLabel(cell))
+			}
+		}
+
+		builder.WriteString(strings.Join(cells, separator))
+		builder.WriteString(fmt.Sprintf(" %d", z))
+
+		if z == height/2 {
+
+			builder.WriteString(" z")
+		}
+
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString(strings.Repeat("-", lineWidth) + " 0\n")
+
+	return builder.String()
+}
+
+func getHorizontal(brick Brick, plane Plane) Range {
+
+	if plane == YZ {
+
+		return brick.y
+	}
+
+	return brick.x
+}
+
+func getVoxelExport(graph SupportGraph) VoxelExport {
+
+	var export VoxelExport
+
+	for index, brick := range graph.bricks {
+
+		voxelBrick := VoxelBrick{
+			Id:        index,
+			Label:     getLabel(index),
+			Unsafe:    graph.chainSizes[index] > 0,
+			ChainSize: graph.chainSizes[index],
+			Supports:  append([]int{}, graph.above[index]...),
+			RestsOn:   append([]int{}, graph.below[index]...),
+		}
+
+		for x := brick.x.lower; x <= brick.x.upper; x++ {
+
+			for y := brick.y.lower; y <= brick.y.upper; y++ {
+
+				for z := brick.z.lower; z <= brick.z.upper; z++ {
+
+					voxelBrick.Voxels = append(voxelBrick.Voxels, [3]int{x, y, z})
+				}
+			}
+		}
+
+		export.Size = [3]int{max(export.Size[0], brick.x.upper+1), max(export.Size[1], brick.y.upper+1), max(export.Size[2], brick.z.upper+1)}
+		export.Bricks = append(export.Bricks, voxelBrick)
+	}
+
+	return export
+}
+
+func writeVoxelJSON(writer io.Writer, graph SupportGraph) error {
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(getVoxelExport(graph))
+}
+
+func Part1(input string) string {
+
+	content := GetContent(input)
+
+	bricks := parseBricks(content)
+
+	bricks, below, _ := settleBricks(bricks)
+
+	return strconv.Itoa(getNumberOfSafeBricks(buildSupportGraph(bricks, below)))
+}
+
+func Part2(input string) string {
+
+	content := GetContent(input)
+
+	bricks := parseBricks(content)
+
+	bricks, below, _ := settleBricks(bricks)
+
+	return strconv.Itoa(getTotalChainReaction(buildSupportGraph(bricks, below)))
 }
 
 func GetContent(filepath string) string {
@@ -325,6 +712,31 @@ func stringToNumber(s string) int {
 
 func main() {
 
+	render := flag.Bool("render", false, "print the x-z and y-z projections of the settled stack")
+	voxels := flag.Bool("voxels", false, "print the settled stack as JSON voxels")
+	flag.Parse()
+
 	fmt.Println(fmt.Sprintf("Part 1: %s", Part1(fmt.Sprintf("input/%s/in.txt", DAY))))
 	fmt.Println(fmt.Sprintf("Part 2: %s", Part2(fmt.Sprintf("input/%s/in.txt", DAY))))
+
+	if !*render && !*voxels {
+
+		return
+	}
+
+	bricks, below, _ := settleBricks(parseBricks(GetContent(fmt.Sprintf("input/%s/in.txt", DAY))))
+
+	if *render {
+
+		fmt.Println(renderProjection(bricks, XZ))
+		fmt.Println(renderProjection(bricks, YZ))
+	}
+
+	if *voxels {
+
+		if err := writeVoxelJSON(os.Stdout, buildSupportGraph(bricks, below)); err != nil {
+
+			log.Fatal(err)
+		}
+	}
 }
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"math/rand"
+	"strings"
 	"testing"
 )
 
@@ -11,6 +14,149 @@ var P1_OUT_TEST = []string{"5"}
 var P2_IN_TEST = []string{fmt.Sprintf("test/%s/in01.txt", DAY)}
 var P2_OUT_TEST = []string{"7"}
 
+func TestSupportGraph(t *testing.T) {
+
+	testIn := P1_IN_TEST[0]
+	bricks, below, _ := settleBricks(parseBricks(GetContent(testIn)))
+	graph := buildSupportGraph(bricks, below)
+
+	assert("chainSizes", testIn, "[6 0 0 0 0 1 0]", fmt.Sprint(graph.chainSizes), t)
+	assert("dominators", testIn, "[7 0 0 0 0 0 5]", fmt.Sprint(graph.dominators), t)
+	assert("getFallingBricks", testIn, "[3 4 5 6]", fmt.Sprint(getFallingBricks(graph, []int{1, 2})), t)
+	assert("getFallingBricks", testIn, "[]", fmt.Sprint(getFallingBricks(graph, []int{3})), t)
+	assert("getFallingBricks", testIn, "[6]", fmt.Sprint(getFallingBricks(graph, []int{5})), t)
+	assert("getRestingOn", testIn, "[0 1 2 3 4 5]", fmt.Sprint(getRestingOn(graph, 6)), t)
+	assert("getRestingOn", testIn, "[]", fmt.Sprint(getRestingOn(graph, 0)), t)
+	assert("getCriticalSupports", testIn, "[5 0]", fmt.Sprint(getCriticalSupports(graph, 6)), t)
+	assert("getCriticalSupports", testIn, "[0]", fmt.Sprint(getCriticalSupports(graph, 3)), t)
+}
+
+func TestSettleBricks(t *testing.T) {
+
+	random := rand.New(rand.NewSource(22))
+
+	for run := 0; run < 50; run++ {
+
+		bricks := generateStack(random, 1+random.Intn(300), 1+random.Intn(10))
+		input := fmt.Sprintf("run %d with %d bricks", run, len(bricks))
+
+		expected, expectedDropped := dropBricks(bricks)
+		received, below, receivedDropped := settleBricks(bricks)
+
+		assert("settleBricks", input, fmt.Sprint(expected), fmt.Sprint(received), t)
+		assert("settleBricks", input, fmt.Sprint(expectedDropped), fmt.Sprint(receivedDropped), t)
+		assert("settleBricks", input, fmt.Sprint(getSupportsPairwise(expected)), fmt.Sprint(below), t)
+	}
+}
+
+func generateStack(random *rand.Rand, count int, size int) Bricks {
+
+	var bricks Bricks
+
+	occupied := make(map[[3]int]bool)
+
+	for len(bricks) < count {
+
+		start := [3]int{random.Intn(size), random.Intn(size), 1 + random.Intn(3*count)}
+		axis := random.Intn(3)
+		length := random.Intn(4)
+		end := start
+		end[axis] += length
+
+		if end[0] >= size || end[1] >= size {
+
+			continue
+		}
+
+		free := true
+
+		for i := 0; i <= length; i++ {
+
+			cell := start
+			cell[axis] += i
+			free = free && !occupied[cell]
+		}
+
+		if !free {
+
+			continue
+		}
+
+		for i := 0; i <= length; i++ {
+
+			cell := start
+			cell[axis] += i
+			occupied[cell] = true
+		}
+
+		bricks = append(bricks, Brick{
+			x: Range{lower: start[0], upper: end[0]},
+			y: Range{lower: start[1], upper: end[1]},
+			z: Range{lower: start[2], upper: end[2]},
+		})
+	}
+
+	return bricks
+}
+
+func TestRenderProjection(t *testing.T) {
+
+	testIn := P1_IN_TEST[0]
+	bricks := parseBricks(GetContent(testIn))
+	settledBricks, _, _ := settleBricks(bricks)
+
+	testOut := []string{
+		" x\n012\n.G. 9\n.G. 8\n... 7\nFFF 6\n..E 5\nD.. 4 z\nCCC 3\nBBB 2\n.A. 1\n--- 0\n",
+		" x\n012\n.G. 6\n.G. 5\nFFF 4\nD.E 3 z\n??? 2\n.A. 1\n--- 0\n",
+		" y\n012\n.G. 6\n.G. 5\n.F. 4\n??? 3 z\nB.C 2\nAAA 1\n--- 0\n",
+	}
+
+	assert("renderProjection", testIn, testOut[0], renderProjection(bricks, XZ), t)
+	assert("renderProjection", testIn, testOut[1], renderProjection(settledBricks, XZ), t)
+	assert("renderProjection", testIn, testOut[2], renderProjection(settledBricks, YZ), t)
+	// 28 single cubes in a row get the two letter labels of the JSON export
+	var row Bricks
+
+	for x := 0; x < 28; x++ {
+
+		row = append(row, Brick{x: Range{lower: x, upper: x}, y: Range{lower: 0, upper: 0}, z: Range{lower: 1, upper: 1}})
+	}
+
+	lines := strings.Split(renderProjection(row, XZ), "\n")
+
+	assert("renderProjection", "28 bricks", " 0  1  2", lines[1][:8], t)
+	assert("renderProjection", "28 bricks", " A  B  C", lines[2][:8], t)
+	assert("renderProjection", "28 bricks", " Z AA AB 1", lines[2][len(lines[2])-10:], t)
+	assert("renderProjection", "28 bricks", fmt.Sprint(len(lines[2])-2), fmt.Sprint(len(lines[3])-2), t)
+	assert("renderProjection", "28 bricks", " y\n 0\n?? 1\n-- 0\n", renderProjection(row, YZ), t)
+
+	assert("getLabel", "0 25 26 701 702", "A Z AA ZZ AAA", strings.Join([]string{getLabel(0), getLabel(25), getLabel(26), getLabel(701), getLabel(702)}, " "), t)
+}
+
+func TestWriteVoxelJSON(t *testing.T) {
+
+	testIn := P1_IN_TEST[0]
+	bricks, below, _ := settleBricks(parseBricks(GetContent(testIn)))
+
+	var builder strings.Builder
+
+	if err := writeVoxelJSON(&builder, buildSupportGraph(bricks, below)); err != nil {
+
+		t.Fatal(err)
+	}
+
+	var export VoxelExport
+
+	if err := json.Unmarshal([]byte(builder.String()), &export); err != nil {
+
+		t.Fatal(err)
+	}
+
+	assert("writeVoxelJSON", testIn, "[3 3 7]", fmt.Sprint(export.Size), t)
+	assert("writeVoxelJSON", testIn, "{0 A true 6 [1 2] [] [[1 0 1] [1 1 1] [1 2 1]]}", fmt.Sprint(export.Bricks[0]), t)
+	assert("writeVoxelJSON", testIn, "{6 G false 0 [] [5] [[1 1 5] [1 1 6]]}", fmt.Sprint(export.Bricks[6]), t)
+}
+
 func TestPart1(t *testing.T) {
 
 	for index, element := range P1_IN_TEST {
@@ -0,0 +1,74 @@
+package holidayhash
+
+import (
+	"hash"
+)
+
+// Size of a HASH checksum in bytes
+const Size = 1
+
+// Digest computes the Holiday ASCII String Helper algorithm: add each byte, multiply by 17 and keep the remainder of 256
+type Digest struct {
+	value uint8
+}
+
+var _ hash.Hash = (*Digest)(nil)
+
+func New() *Digest {
+
+	return &Digest{}
+}
+
+func (d *Digest) Write(p []byte) (int, error) {
+
+	for _, character := range p {
+
+		// uint8 arithmetic wraps around at 256 by itself
+		d.value = (d.value + character) * 17
+	}
+
+	return len(p), nil
+}
+
+func (d *Digest) WriteString(s string) (int, error) {
+
+	return d.Write([]byte(s))
+}
+
+func (d *Digest) Sum(b []byte) []byte {
+
+	return append(b, d.value)
+}
+
+func (d *Digest) Sum8() uint8 {
+
+	return d.value
+}
+
+func (d *Digest) Reset() {
+
+	d.value = 0
+}
+
+func (d *Digest) Size() int {
+
+	return Size
+}
+
+func (d *Digest) BlockSize() int {
+
+	return 1
+}
+
+func Sum8(data []byte) uint8 {
+
+	digest := New()
+	digest.Write(data)
+
+	return digest.Sum8()
+}
+
+func String(s string) int {
+
+	return int(Sum8([]byte(s)))
+}
@@ -0,0 +1,36 @@
+package holidayhash
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDigest(t *testing.T) {
+
+	testIn := []string{"HASH", "rn=1", "cm-", "qp", ""}
+	testOut := []int{52, 30, 253, 1, 0}
+
+	for index := range testIn {
+
+		assert("String", testIn[index], fmt.Sprintf("%d", testOut[index]), fmt.Sprintf("%d", String(testIn[index])), t)
+	}
+
+	// writing in chunks yields the same checksum
+	digest := New()
+	digest.WriteString("HA")
+	digest.WriteString("SH")
+
+	assert("Sum", "HA+SH", "[52]", fmt.Sprintf("%v", digest.Sum(nil)), t)
+
+	digest.Reset()
+
+	assert("Reset", "HASH", "0", fmt.Sprintf("%d", digest.Sum8()), t)
+}
+
+func assert(method string, input string, expected string, received string, t *testing.T) {
+
+	if expected != received {
+
+		t.Errorf("%s(%s) expected '%s' but received '%s'", method, input, expected, received)
+	}
+}
@@ -0,0 +1,174 @@
+package polygon
+
+import (
+	"fmt"
+)
+
+type Point struct {
+	X int
+	Y int
+}
+
+// NotClosedError reports a path whose last vertex differs from its first
+type NotClosedError struct {
+	Start Point
+	End   Point
+}
+
+func (e *NotClosedError) Error() string {
+
+	return fmt.Sprintf("path starts at (%d, %d) but ends at (%d, %d)", e.Start.X, e.Start.Y, e.End.X, e.End.Y)
+}
+
+// IntersectionError reports two edges that touch outside a shared vertex, edge i runs from vertex i to vertex i + 1
+type IntersectionError struct {
+	First  int
+	Second int
+}
+
+func (e *IntersectionError) Error() string {
+
+	return fmt.Sprintf("edges %d and %d intersect", e.First, e.Second)
+}
+
+// all functions expect closed paths, the first vertex is repeated at the end
+
+func DoubleArea(vertices []Point) int {
+
+	sum := 0
+
+	for i := 0; i < len(vertices)-1; i++ {
+
+		sum += vertices[i].X*vertices[i+1].Y - vertices[i+1].X*vertices[i].Y
+	}
+
+	return abs(sum)
+}
+
+// Boundary counts the lattice points on the edges
+func Boundary(vertices []Point) int {
+
+	sum := 0
+
+	for i := 0; i < len(vertices)-1; i++ {
+
+		sum += gcd(abs(vertices[i+1].X-vertices[i].X), abs(vertices[i+1].Y-vertices[i].Y))
+	}
+
+	return sum
+}
+
+// Interior counts the lattice points strictly inside by Pick's theorem A = I + B/2 - 1
+func Interior(vertices []Point) int {
+
+	return (DoubleArea(vertices)-Boundary(vertices))/2 + 1
+}
+
+func Validate(vertices []Point) error {
+
+	if len(vertices) == 0 {
+
+		return nil
+	}
+
+	if vertices[0] != vertices[len(vertices)-1] {
+
+		return &NotClosedError{Start: vertices[0], End: vertices[len(vertices)-1]}
+	}
+
+	edges := len(vertices) - 1
+
+	for first := 0; first < edges; first++ {
+
+		for second := first + 1; second < edges; second++ {
+
+			adjacent := second == first+1 || (first == 0 && second == edges-1)
+
+			if adjacent && edges > 2 {
+
+				if overlapsAtSharedVertex(vertices, first, second, edges) {
+
+					return &IntersectionError{First: first, Second: second}
+				}
+				continue
+			}
+
+			if segmentsIntersect(vertices[first], vertices[first+1], vertices[second], vertices[second+1]) {
+
+				return &IntersectionError{First: first, Second: second}
+			}
+		}
+	}
+
+	return nil
+}
+
+// adjacent edges only share their common vertex unless the second one turns back onto the first
+func overlapsAtSharedVertex(vertices []Point, first int, second int, edges int) bool {
+
+	before, shared, after := vertices[first], vertices[first+1], vertices[second+1]
+
+	if first == 0 && second == edges-1 {
+
+		before, shared, after = vertices[second], vertices[0], vertices[1]
+	}
+
+	incoming := Point{X: shared.X - before.X, Y: shared.Y - before.Y}
+	outgoing := Point{X: after.X - shared.X, Y: after.Y - shared.Y}
+
+	return incoming.X*outgoing.Y-incoming.Y*outgoing.X == 0 && incoming.X*outgoing.X+incoming.Y*outgoing.Y < 0
+}
+
+func orientation(a Point, b Point, c Point) int {
+
+	cross := (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+
+	switch {
+	case cross > 0:
+		return 1
+	case cross < 0:
+		return -1
+	}
+
+	return 0
+}
+
+func onSegment(a Point, b Point, p Point) bool {
+
+	return min(a.X, b.X) <= p.X && p.X <= max(a.X, b.X) && min(a.Y, b.Y) <= p.Y && p.Y <= max(a.Y, b.Y)
+}
+
+func segmentsIntersect(a Point, b Point, c Point, d Point) bool {
+
+	o1 := orientation(a, b, c)
+	o2 := orientation(a, b, d)
+	o3 := orientation(c, d, a)
+	o4 := orientation(c, d, b)
+
+	if o1 != o2 && o3 != o4 {
+
+		return true
+	}
+
+	return (o1 == 0 && onSegment(a, b, c)) || (o2 == 0 && onSegment(a, b, d)) || (o3 == 0 && onSegment(c, d, a)) || (o4 == 0 && onSegment(c, d, b))
+}
+
+func abs(x int) int {
+
+	if x < 0 {
+
+		return -x
+	}
+
+	return x
+}
+
+func gcd(a int, b int) int {
+
+	if b == 0 {
+
+		return a
+	}
+
+	return gcd(b, a%b)
+}
@@ -0,0 +1,57 @@
+package polygon
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestMeasures(t *testing.T) {
+
+	// a 4 by 3 rectangle and a right triangle with a diagonal edge
+	testIn := [][]Point{
+		{{0, 0}, {4, 0}, {4, 3}, {0, 3}, {0, 0}},
+		{{0, 0}, {4, 0}, {0, 4}, {0, 0}},
+	}
+	testOut := []string{"24 14 6", "16 12 3"}
+
+	for index, vertices := range testIn {
+
+		received := fmt.Sprintf("%d %d %d", DoubleArea(vertices), Boundary(vertices), Interior(vertices))
+		assert("measures", fmt.Sprintf("%v", vertices), testOut[index], received, t)
+	}
+}
+
+func TestValidate(t *testing.T) {
+
+	testIn := [][]Point{
+		{{0, 0}, {4, 0}, {4, 3}, {0, 3}, {0, 0}},
+		{{0, 0}, {4, 0}, {4, 3}},
+		{{0, 0}, {2, 0}, {2, 2}, {1, 2}, {1, -1}, {0, -1}, {0, 0}},
+		{{0, 0}, {4, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}},
+	}
+	testOut := []string{"<nil>", "not closed", "edges 0 and 3 intersect", "edges 0 and 1 intersect"}
+
+	for index, vertices := range testIn {
+
+		err := Validate(vertices)
+		received := fmt.Sprintf("%v", err)
+
+		var notClosed *NotClosedError
+
+		if errors.As(err, &notClosed) {
+
+			received = "not closed"
+		}
+
+		assert("Validate", fmt.Sprintf("%v", vertices), testOut[index], received, t)
+	}
+}
+
+func assert(method string, input string, expected string, received string, t *testing.T) {
+
+	if expected != received {
+
+		t.Errorf("%s(%s) expected '%s' but received '%s'", method, input, expected, received)
+	}
+}